@@ -0,0 +1,209 @@
+// Package bulkdocs distributes a document, the same for everyone or
+// personalised per employee, to many employees at once.
+package bulkdocs
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/arexio/factorial-go"
+)
+
+// Status of the distribution to an employee
+const (
+	StatusCreated = "created"
+	StatusSkipped = "skipped" // Already distributed by a previous run
+	StatusFailed  = "failed"
+)
+
+var _ Client = factorial.Client{}
+
+// Client is the subset of the factorial.Client used for distribute the documents
+type Client interface {
+	ListEmployees() ([]factorial.Employee, error)
+	CreateDocument(d factorial.CreateDocumentRequest) (factorial.Document, error)
+}
+
+// Template builds the document for each employee
+type Template struct {
+	// FileName is a text/template executed with the factorial.Employee,
+	// e.g. "Certificate {{.FullName}}.pdf"
+	FileName string
+	// Content is the file sent to every employee, used when Render is nil
+	Content []byte
+	// Render returns the personalised content for the employee
+	Render func(e factorial.Employee) ([]byte, error)
+	// ContentType of the content, detected from it if empty
+	ContentType string
+}
+
+// Options changes how the documents are distributed
+type Options struct {
+	FolderID          int
+	Public            bool
+	RequestESignature bool // The employee is requested to sign their document
+	// Concurrency is the number of documents created at the same time, 4 by default
+	Concurrency int
+	// Filter selects the employees, all the not terminated ones if nil
+	Filter func(e factorial.Employee) bool
+	// Progress keeps the employees already served, for resume interrupted runs
+	Progress Progress
+}
+
+// Result is the outcome of the distribution to an employee
+type Result struct {
+	EmployeeID int
+	FullName   string
+	FileName   string
+	DocumentID int
+	Status     string
+	Error      string
+}
+
+// Report holds the result for each employee, in the order of ListEmployees
+type Report struct {
+	Results []Result
+}
+
+// Count returns the number of results with the given status
+func (r Report) Count(status string) int {
+	var n int
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteCSV writes the report as CSV, one line per employee
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"employee_id", "full_name", "filename", "document_id", "status", "error"}); err != nil {
+		return err
+	}
+	for _, res := range r.Results {
+		documentID := ""
+		if res.DocumentID != 0 {
+			documentID = strconv.Itoa(res.DocumentID)
+		}
+		if err := cw.Write([]string{strconv.Itoa(res.EmployeeID), res.FullName, res.FileName, documentID, res.Status, res.Error}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Distribute creates one document per selected employee using the template.
+// Failures don't stop the distribution, they are reported per employee.
+func Distribute(c Client, t Template, opts Options) (Report, error) {
+	var report Report
+
+	if t.Render == nil && len(t.Content) == 0 {
+		return report, errors.New("bulkdocs: template has no content")
+	}
+	fileName, err := template.New("filename").Parse(t.FileName)
+	if err != nil {
+		return report, err
+	}
+
+	employees, err := c.ListEmployees()
+	if err != nil {
+		return report, err
+	}
+	filter := opts.Filter
+	if filter == nil {
+		filter = func(e factorial.Employee) bool { return e.TerminatedOn == "" }
+	}
+	var selected []factorial.Employee
+	for _, e := range employees {
+		if filter(e) {
+			selected = append(selected, e)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	report.Results = make([]Result, len(selected))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, e := range selected {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, e factorial.Employee) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			report.Results[i] = distribute(c, t, fileName, opts, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	return report, nil
+}
+
+// distribute creates the document of a single employee
+func distribute(c Client, t Template, fileName *template.Template, opts Options, e factorial.Employee) Result {
+	result := Result{EmployeeID: e.ID, FullName: e.FullName}
+	fail := func(err error) Result {
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result
+	}
+
+	var name bytes.Buffer
+	if err := fileName.Execute(&name, e); err != nil {
+		return fail(err)
+	}
+	result.FileName = strings.TrimSpace(name.String())
+
+	if opts.Progress != nil {
+		if documentID, ok := opts.Progress.Done(e.ID); ok {
+			result.Status, result.DocumentID = StatusSkipped, documentID
+			return result
+		}
+	}
+
+	content := t.Content
+	if t.Render != nil {
+		var err error
+		if content, err = t.Render(e); err != nil {
+			return fail(err)
+		}
+	}
+
+	req := factorial.CreateDocumentRequest{
+		Public:            opts.Public,
+		EmployeeID:        e.ID,
+		File:              factorial.DocumentFile(content, t.ContentType),
+		FileName:          result.FileName,
+		FolderID:          opts.FolderID,
+		RequestESignature: opts.RequestESignature,
+	}
+	if opts.RequestESignature {
+		req.Signees = []int{e.ID}
+	}
+	document, err := c.CreateDocument(req)
+	if err != nil {
+		return fail(err)
+	}
+	result.Status, result.DocumentID = StatusCreated, document.ID
+
+	if opts.Progress != nil {
+		if err := opts.Progress.Mark(e.ID, document.ID); err != nil {
+			// The document exists, so it's reported as created
+			// and a resumed run may create it again
+			result.Error = "progress not saved: " + err.Error()
+		}
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package bulkdocs
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/arexio/factorial-go/internal/progress"
+)
+
+// Progress keeps the employees that already received their
+// document, so an interrupted distribution can be resumed
+type Progress interface {
+	Done(employeeID int) (documentID int, ok bool)
+	Mark(employeeID, documentID int) error
+}
+
+// FileProgress is a Progress persisted on a file, with
+// a line "employee_id document_id" per employee served
+type FileProgress struct {
+	mu   sync.Mutex
+	file *progress.File
+	done map[int]int
+}
+
+// NewFileProgress loads the progress from the given file,
+// an empty progress is returned if the file doesn't exist
+func NewFileProgress(path string) (*FileProgress, error) {
+	p := &FileProgress{done: make(map[int]int)}
+
+	file, err := progress.Open(path, func(line string) error {
+		var employeeID, documentID int
+		if _, err := fmt.Sscan(line, &employeeID, &documentID); err != nil {
+			return fmt.Errorf("bulkdocs: invalid progress line %q", line)
+		}
+		p.done[employeeID] = documentID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.file = file
+
+	return p, nil
+}
+
+// Done returns the document created for the employee, if any
+func (p *FileProgress) Done(employeeID int) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	documentID, ok := p.done[employeeID]
+	return documentID, ok
+}
+
+// Mark saves the document created for the employee
+func (p *FileProgress) Mark(employeeID, documentID int) error {
+	if err := p.file.Append(employeeID, documentID); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done[employeeID] = documentID
+	return nil
+}
@@ -0,0 +1,187 @@
+// Package celebrations computes the upcoming birthdays and work
+// anniversaries of the employees, for announcing them.
+package celebrations
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/teams"
+)
+
+// MaxDays is the longest window of events, a year without repeating
+// a calendar day
+const MaxDays = 365
+
+// Types of event
+const (
+	TypeBirthday    = "birthday"
+	TypeAnniversary = "work_anniversary"
+)
+
+// Ways of grouping the events
+const (
+	GroupNone     = ""
+	GroupTeam     = "team"
+	GroupLocation = "location"
+)
+
+// Input is the data the events are computed from
+type Input struct {
+	Employees []factorial.Employee
+	Teams     []factorial.Team
+	Locations []factorial.Location
+}
+
+// Options changes which events are returned
+type Options struct {
+	// Days of the window starting on the given date, 7 by default
+	// and MaxDays at most
+	Days int
+	// OptOut are the employees that don't want their events announced
+	OptOut []int
+	// LeapDayOnMarch1 celebrates the events of February 29 on March 1
+	// of non-leap years, instead of February 28
+	LeapDayOnMarch1 bool
+}
+
+// Event is a birthday or work anniversary of an employee. The age
+// is not included, only the years of the anniversaries.
+type Event struct {
+	Type       string `json:"type"`
+	EmployeeID int    `json:"employee_id"`
+	FullName   string `json:"full_name"`
+	Date       string `json:"date"`
+	Years      int    `json:"years,omitempty"` // Years in the company, for anniversaries
+}
+
+// Group holds the events of a team or location
+type Group struct {
+	Name   string  `json:"name"`
+	Events []Event `json:"events"`
+}
+
+// Upcoming returns the events between from and the end of the window,
+// sorted by date and name. Terminated employees, the ones that haven't
+// started yet and the ones that opted out are excluded.
+func Upcoming(employees []factorial.Employee, from time.Time, opts Options) []Event {
+	days := opts.Days
+	if days <= 0 {
+		days = 7
+	}
+	if days > MaxDays {
+		days = MaxDays
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, days)
+	optOut := make(map[int]bool)
+	for _, id := range opts.OptOut {
+		optOut[id] = true
+	}
+
+	var events []Event
+	for _, e := range employees {
+		if optOut[e.ID] || (e.TerminatedOn != "" && e.TerminatedOn < start.Format(factorial.DateLayout)) {
+			continue
+		}
+		startDate, err := time.Parse(factorial.DateLayout, e.StartDate)
+		if err == nil && startDate.After(start) {
+			continue
+		}
+
+		if birthday, err := time.Parse(factorial.DateLayout, e.BirthdayOn); err == nil {
+			for _, date := range occurrences(birthday, start, end, opts.LeapDayOnMarch1) {
+				events = append(events, Event{Type: TypeBirthday, EmployeeID: e.ID, FullName: e.FullName, Date: date.Format(factorial.DateLayout)})
+			}
+		}
+		if err == nil {
+			for _, date := range occurrences(startDate, start, end, opts.LeapDayOnMarch1) {
+				years := date.Year() - startDate.Year()
+				if years < 1 {
+					continue
+				}
+				events = append(events, Event{Type: TypeAnniversary, EmployeeID: e.ID, FullName: e.FullName, Date: date.Format(factorial.DateLayout), Years: years})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Date != events[j].Date {
+			return events[i].Date < events[j].Date
+		}
+		if events[i].FullName != events[j].FullName {
+			return events[i].FullName < events[j].FullName
+		}
+		return events[i].Type < events[j].Type
+	})
+	return events
+}
+
+// GroupEvents splits the events by the team or location of their employees,
+// employees in several teams appear on each. Groups are sorted by name.
+func GroupEvents(in Input, events []Event, groupBy string) []Group {
+	names := make(map[int][]string)
+	switch groupBy {
+	case GroupTeam:
+		names = teams.ByEmployee(in.Teams, in.Employees)
+	case GroupLocation:
+		locations := make(map[int]string)
+		for _, l := range in.Locations {
+			locations[l.ID] = l.Name
+		}
+		for _, e := range in.Employees {
+			if name, ok := locations[e.LocationID]; ok {
+				names[e.ID] = []string{name}
+			}
+		}
+	default:
+		return []Group{{Name: "All", Events: events}}
+	}
+
+	byName := make(map[string][]Event)
+	for _, ev := range events {
+		groups := names[ev.EmployeeID]
+		if len(groups) == 0 {
+			groups = []string{"Unknown"}
+		}
+		for _, name := range groups {
+			byName[name] = append(byName[name], ev)
+		}
+	}
+	groups := make([]Group, 0, len(byName))
+	for name, events := range byName {
+		groups = append(groups, Group{Name: name, Events: events})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// occurrences returns the dates of the yearly event of the given
+// date between start, inclusive, and end, exclusive
+func occurrences(date, start, end time.Time, leapDayOnMarch1 bool) []time.Time {
+	var dates []time.Time
+	for year := start.Year(); year <= end.Year(); year++ {
+		d := anniversary(date, year, leapDayOnMarch1)
+		if !d.Before(start) && d.Before(end) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// anniversary returns the date of the given year with the month and day
+// of date, February 29 falls on February 28 or March 1 on non-leap years
+func anniversary(date time.Time, year int, leapDayOnMarch1 bool) time.Time {
+	if date.Month() == time.February && date.Day() == 29 && !isLeap(year) {
+		if leapDayOnMarch1 {
+			return time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		}
+		return time.Date(year, time.February, 28, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
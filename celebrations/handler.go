@@ -0,0 +1,92 @@
+package celebrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+var _ Client = factorial.Client{}
+
+// Client is the subset of the factorial.Client used by the Handler
+type Client interface {
+	ListEmployees() ([]factorial.Employee, error)
+	ListTeams() ([]factorial.Team, error)
+	ListLocations() ([]factorial.Location, error)
+}
+
+// Handler serves the upcoming events as JSON. The query parameters
+// "days", up to MaxDays, and "group_by" (team or location) override the options,
+// "from" changes the first day of the window, today by default.
+type Handler struct {
+	Client  Client
+	Options Options
+	GroupBy string
+	// Location used for compute today, time.Local if nil
+	Location *time.Location
+}
+
+// ServeHTTP implements http.Handler
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	opts := h.Options
+	if days := r.URL.Query().Get("days"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 || n > MaxDays {
+			http.Error(w, "invalid days", http.StatusBadRequest)
+			return
+		}
+		opts.Days = n
+	}
+	groupBy := h.GroupBy
+	if g := r.URL.Query().Get("group_by"); g != "" {
+		if g != GroupTeam && g != GroupLocation {
+			http.Error(w, "invalid group_by", http.StatusBadRequest)
+			return
+		}
+		groupBy = g
+	}
+	loc := h.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	from := time.Now().In(loc)
+	if f := r.URL.Query().Get("from"); f != "" {
+		var err error
+		if from, err = time.ParseInLocation(factorial.DateLayout, f, loc); err != nil {
+			http.Error(w, "invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var in Input
+	var err error
+	if in.Employees, err = h.Client.ListEmployees(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if groupBy == GroupTeam {
+		if in.Teams, err = h.Client.ListTeams(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+	if groupBy == GroupLocation {
+		if in.Locations, err = h.Client.ListLocations(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	groups := GroupEvents(in, Upcoming(in.Employees, from, opts), groupBy)
+	for i := range groups {
+		if groups[i].Events == nil {
+			groups[i].Events = []Event{}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
@@ -0,0 +1,130 @@
+package factorial
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// defaultToggleWindow is the time after a clock in or clock out
+// in which ToggleClock ignores new taps, shift clocks have minute
+// precision so it should be bigger than a minute
+const defaultToggleWindow = 2 * time.Minute
+
+// Actions performed by ToggleClock
+const (
+	ToggleClockIn      = "clock_in"
+	ToggleClockOut     = "clock_out"
+	ToggleClockIgnored = "ignored" // Duplicated tap or already done by a concurrent call
+)
+
+// ToggleClockResult holds the action done by ToggleClock
+// and the shift affected by it
+type ToggleClockResult struct {
+	Action string
+	Shift  Shift
+}
+
+// ToggleClock clocks out the employee if there is an open shift, or clocks in otherwise.
+// Taps within the toggle window of the last clock in or clock out are ignored, as well
+// as the 422 errors produced when a concurrent call has already done the same action.
+// The given now should be on the employee's time zone, see EmployeeTimeZone.
+func (c Client) ToggleClock(employeeID int, now time.Time) (ToggleClockResult, error) {
+	open, last, err := c.lastShifts(employeeID, now)
+	if err != nil {
+		return ToggleClockResult{}, err
+	}
+
+	if open != nil {
+		clockIn, _ := open.ClockInTime(now.Location())
+		if c.withinToggleWindow(clockIn, now) {
+			return ToggleClockResult{Action: ToggleClockIgnored, Shift: *open}, nil
+		}
+		shift, err := c.ClockOut(ClockOutRequest{
+			Now:        now,
+			EmployeeID: employeeID,
+		})
+		if IsUnprocessable(err) {
+			// Someone else closed the shift since we checked it
+			return ToggleClockResult{Action: ToggleClockIgnored, Shift: *open}, nil
+		}
+		if err != nil {
+			return ToggleClockResult{}, err
+		}
+		return ToggleClockResult{Action: ToggleClockOut, Shift: shift}, nil
+	}
+
+	if last != nil && c.withinToggleWindow(lastClockOut(*last, now.Location()), now) {
+		return ToggleClockResult{Action: ToggleClockIgnored, Shift: *last}, nil
+	}
+	shift, err := c.ClockIn(ClockInRequest{
+		Now:        now,
+		EmployeeID: employeeID,
+	})
+	if IsUnprocessable(err) {
+		// Someone else opened a shift since we checked it
+		open, _, err := c.lastShifts(employeeID, now)
+		if err != nil {
+			return ToggleClockResult{}, err
+		}
+		result := ToggleClockResult{Action: ToggleClockIgnored}
+		if open != nil {
+			result.Shift = *open
+		}
+		return result, nil
+	}
+	if err != nil {
+		return ToggleClockResult{}, err
+	}
+	return ToggleClockResult{Action: ToggleClockIn, Shift: shift}, nil
+}
+
+// lastShifts returns the open shift of the employee, if any, and the last
+// closed one, looking at the shifts of the current and the previous month
+func (c Client) lastShifts(employeeID int, now time.Time) (*Shift, *Shift, error) {
+	var open, last *Shift
+
+	// The first day avoids AddDate overflowing, March 31 minus a month is March 2
+	previous := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+	for _, month := range []time.Time{previous, now} {
+		filter := url.Values{}
+		filter.Set("year", strconv.Itoa(month.Year()))
+		filter.Set("month", strconv.Itoa(int(month.Month())))
+		shifts, err := c.ListShifts(filter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range shifts {
+			s := shifts[i]
+			if s.EmployeeID != employeeID {
+				continue
+			}
+			if s.IsOpen() {
+				open = &s
+				continue
+			}
+			if last == nil || lastClockOut(s, now.Location()).After(lastClockOut(*last, now.Location())) {
+				last = &s
+			}
+		}
+	}
+
+	return open, last, nil
+}
+
+// withinToggleWindow returns whether the given time is
+// close enough to now for be considered a duplicated tap
+func (c Client) withinToggleWindow(t, now time.Time) bool {
+	if c.toggleWindow <= 0 || t.IsZero() {
+		return false
+	}
+	return !t.After(now) && now.Sub(t) < c.toggleWindow
+}
+
+// lastClockOut returns the clock out of the closed shift,
+// or the zero time if it can't be parsed
+func lastClockOut(s Shift, loc *time.Location) time.Time {
+	t, _ := s.ClockOutTime(loc)
+	return t
+}
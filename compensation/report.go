@@ -0,0 +1,122 @@
+package compensation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+// Report is the compensation changes of the company on a period
+type Report struct {
+	From    time.Time
+	To      time.Time // Inclusive
+	Changes []ReportChange
+}
+
+// ReportChange is a change with the name of the employee
+type ReportChange struct {
+	FullName string `json:"full_name"`
+	Change
+}
+
+// NewReport returns the changes effective between from and to, both inclusive,
+// of every employee, sorted by date and employee. Employees are only used for
+// their names and may be nil.
+func NewReport(versions []factorial.HiringVersion, employees []factorial.Employee, from, to time.Time) Report {
+	r := Report{From: from, To: to}
+
+	names := make(map[int]string)
+	for _, e := range employees {
+		names[e.ID] = e.FullName
+	}
+	first, last := from.Format(factorial.DateLayout), to.Format(factorial.DateLayout)
+	for _, t := range NewTimelines(versions) {
+		for _, c := range t.Changes() {
+			if c.EffectiveOn < first || c.EffectiveOn > last {
+				continue
+			}
+			r.Changes = append(r.Changes, ReportChange{FullName: names[c.EmployeeID], Change: c})
+		}
+	}
+	sort.Slice(r.Changes, func(i, j int) bool {
+		if r.Changes[i].EffectiveOn != r.Changes[j].EffectiveOn {
+			return r.Changes[i].EffectiveOn < r.Changes[j].EffectiveOn
+		}
+		return r.Changes[i].EmployeeID < r.Changes[j].EmployeeID
+	})
+	return r
+}
+
+// QuarterReport returns the report of the quarter, from 1 to 4, of the year.
+// An error is returned for any other quarter.
+func QuarterReport(versions []factorial.HiringVersion, employees []factorial.Employee, year, quarter int) (Report, error) {
+	if quarter < 1 || quarter > 4 {
+		return Report{}, fmt.Errorf("compensation: invalid quarter %d", quarter)
+	}
+	from := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 3, -1)
+	return NewReport(versions, employees, from, to), nil
+}
+
+// WriteCSV writes the report as CSV, one line per change
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"employee_id", "full_name", "effective_on", "changes", "salary_from_in_cents", "salary_to_in_cents",
+		"raise_in_cents", "raise_percent", "job_title_from", "job_title_to", "working_hours_from", "working_hours_to",
+	}); err != nil {
+		return err
+	}
+	for _, c := range r.Changes {
+		if err := cw.Write([]string{
+			strconv.Itoa(c.EmployeeID),
+			c.FullName,
+			c.EffectiveOn,
+			strings.Join(c.Kinds, ","),
+			strconv.FormatInt(c.SalaryFrom, 10),
+			strconv.FormatInt(c.SalaryTo, 10),
+			strconv.FormatInt(c.Raise, 10),
+			strconv.FormatFloat(c.RaisePercent, 'f', 2, 64),
+			c.JobTitleFrom,
+			c.JobTitleTo,
+			strconv.FormatFloat(c.WorkingHoursFrom, 'f', 2, 64),
+			strconv.FormatFloat(c.WorkingHoursTo, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Print writes the report in a human readable way
+func (r Report) Print(w io.Writer) error {
+	for _, c := range r.Changes {
+		name := c.FullName
+		if name == "" {
+			name = fmt.Sprintf("employee %d", c.EmployeeID)
+		}
+		line := c.EffectiveOn + " " + name + ":"
+		for _, kind := range c.Kinds {
+			switch kind {
+			case ChangeSalary:
+				line += fmt.Sprintf(" salary %.2f -> %.2f (%+.2f%%)", float64(c.SalaryFrom)/100, float64(c.SalaryTo)/100, c.RaisePercent)
+			case ChangeJobTitle:
+				line += fmt.Sprintf(" job title %q -> %q", c.JobTitleFrom, c.JobTitleTo)
+			case ChangeWorkingHours:
+				line += fmt.Sprintf(" weekly hours %.2f -> %.2f", c.WorkingHoursFrom, c.WorkingHoursTo)
+			}
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d change(s) from %s to %s.\n", len(r.Changes), r.From.Format(factorial.DateLayout), r.To.Format(factorial.DateLayout))
+	return err
+}
@@ -0,0 +1,147 @@
+// Package compensation builds the history of the hiring versions of the
+// employees, answering what their contract was on a given date and
+// which raises, job title and working hours changes they had.
+package compensation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/timesheet"
+)
+
+// Kinds of change between two hiring versions
+const (
+	ChangeSalary       = "salary"
+	ChangeJobTitle     = "job_title"
+	ChangeWorkingHours = "working_hours"
+)
+
+// Timeline is the hiring versions of an employee sorted by EffectiveOn
+type Timeline struct {
+	EmployeeID int
+	Versions   []factorial.HiringVersion
+}
+
+// NewTimeline returns the timeline of the employee from the
+// versions of any employee, as returned by ListHiringVersions
+func NewTimeline(versions []factorial.HiringVersion, employeeID int) Timeline {
+	t := Timeline{EmployeeID: employeeID}
+	for _, hv := range versions {
+		if hv.EmployeeID == employeeID {
+			t.Versions = append(t.Versions, hv)
+		}
+	}
+	sortVersions(t.Versions)
+	return t
+}
+
+// NewTimelines returns the timeline of each employee with hiring versions
+func NewTimelines(versions []factorial.HiringVersion) map[int]Timeline {
+	timelines := make(map[int]Timeline)
+	for _, hv := range versions {
+		t := timelines[hv.EmployeeID]
+		t.EmployeeID = hv.EmployeeID
+		t.Versions = append(t.Versions, hv)
+		timelines[hv.EmployeeID] = t
+	}
+	for _, t := range timelines {
+		sortVersions(t.Versions)
+	}
+	return timelines
+}
+
+// At returns the hiring version effective on the given date,
+// see timesheet.EffectiveHiringVersion
+func (t Timeline) At(date time.Time) (factorial.HiringVersion, bool) {
+	return timesheet.EffectiveHiringVersion(t.Versions, t.EmployeeID, date)
+}
+
+// Current returns the latest hiring version
+func (t Timeline) Current() (factorial.HiringVersion, bool) {
+	if len(t.Versions) == 0 {
+		return factorial.HiringVersion{}, false
+	}
+	return t.Versions[len(t.Versions)-1], true
+}
+
+// Change is the difference between a hiring version and the previous one.
+// Salaries are annual, see AnnualSalary, so versions with different
+// compensation types can be compared.
+type Change struct {
+	EmployeeID       int      `json:"employee_id"`
+	EffectiveOn      string   `json:"effective_on"`
+	FromVersionID    int      `json:"from_version_id"`
+	ToVersionID      int      `json:"to_version_id"`
+	Kinds            []string `json:"kinds"`
+	SalaryFrom       int64    `json:"salary_from_in_cents"`
+	SalaryTo         int64    `json:"salary_to_in_cents"`
+	Raise            int64    `json:"raise_in_cents"`
+	RaisePercent     float64  `json:"raise_percent"` // 5 is a 5% raise, 0 if there was no previous salary
+	JobTitleFrom     string   `json:"job_title_from"`
+	JobTitleTo       string   `json:"job_title_to"`
+	WorkingHoursFrom float64  `json:"working_hours_from"` // Weekly hours
+	WorkingHoursTo   float64  `json:"working_hours_to"`
+}
+
+// Changes returns the changes between each version and the previous one,
+// versions that change nothing of the above are skipped
+func (t Timeline) Changes() []Change {
+	var changes []Change
+	for i := 1; i < len(t.Versions); i++ {
+		prev, cur := t.Versions[i-1], t.Versions[i]
+		c := Change{
+			EmployeeID:       t.EmployeeID,
+			EffectiveOn:      cur.EffectiveOn,
+			FromVersionID:    prev.ID,
+			ToVersionID:      cur.ID,
+			SalaryFrom:       AnnualSalary(prev),
+			SalaryTo:         AnnualSalary(cur),
+			JobTitleFrom:     prev.JobTitle,
+			JobTitleTo:       cur.JobTitle,
+			WorkingHoursFrom: timesheet.WeeklyHours(prev).Hours(),
+			WorkingHoursTo:   timesheet.WeeklyHours(cur).Hours(),
+		}
+		c.Raise = c.SalaryTo - c.SalaryFrom
+		if c.SalaryFrom != 0 {
+			c.RaisePercent = float64(c.Raise) * 100 / float64(c.SalaryFrom)
+		}
+		if c.Raise != 0 {
+			c.Kinds = append(c.Kinds, ChangeSalary)
+		}
+		if c.JobTitleFrom != c.JobTitleTo {
+			c.Kinds = append(c.Kinds, ChangeJobTitle)
+		}
+		if c.WorkingHoursFrom != c.WorkingHoursTo {
+			c.Kinds = append(c.Kinds, ChangeWorkingHours)
+		}
+		if len(c.Kinds) > 0 {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// AnnualSalary returns the gross salary per year of the hiring version in
+// cents, hourly compensations are multiplied by the contracted hours
+func AnnualSalary(hv factorial.HiringVersion) int64 {
+	amount := float64(hv.BaseCompensationAmountInCents)
+	switch hv.BaseCompensationType {
+	case factorial.CompensationHourly:
+		return int64(amount*timesheet.WeeklyHours(hv).Hours()*52 + 0.5)
+	case factorial.CompensationMonthly:
+		return int64(amount * 12)
+	default:
+		return int64(amount)
+	}
+}
+
+func sortVersions(versions []factorial.HiringVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].EffectiveOn != versions[j].EffectiveOn {
+			return versions[i].EffectiveOn < versions[j].EffectiveOn
+		}
+		return versions[i].ID < versions[j].ID
+	})
+}
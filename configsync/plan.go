@@ -0,0 +1,437 @@
+package configsync
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arexio/factorial-go"
+)
+
+// Actions that a change can perform
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDeactivate = "deactivate"
+)
+
+// Resources that can be managed
+const (
+	ResourceLeaveType = "leave_type"
+	ResourceFolder    = "folder"
+	ResourceWebhook   = "webhook"
+)
+
+var _ Client = factorial.Client{}
+
+// Client is the subset of the factorial.Client used for sync the configuration
+type Client interface {
+	ListLeaveTypes() ([]factorial.LeaveType, error)
+	CreateLeaveType(lt factorial.CreateLeaveTypeRequest) (factorial.LeaveType, error)
+	UpdateLeaveType(id string, lt factorial.UpdateLeaveTypeRequest) (factorial.LeaveType, error)
+	ListFolders(filter url.Values) ([]factorial.Folder, error)
+	CreateFolder(f factorial.CreateFolderRequest) (factorial.Folder, error)
+	UpdateFolder(id string, f factorial.UpdateFolderRequest) (factorial.Folder, error)
+	ListWebhooks() ([]factorial.Webhook, error)
+	CreateWebhook(w factorial.CreateWebhookRequest) (factorial.Webhook, error)
+	DeleteWebhook(w factorial.DeleteWebhookRequest) (factorial.Webhook, error)
+}
+
+// Options changes how the plan is built and applied
+type Options struct {
+	// DryRun only builds the plan, nothing is applied
+	DryRun bool
+	// Prune deactivates the custom leave types and folders, and deletes
+	// the webhooks, that are not declared on the desired state
+	Prune bool
+}
+
+// Change is a single operation needed to reach the desired state
+type Change struct {
+	Resource string
+	Action   string
+	Name     string
+	Details  []string // Human readable list of the modified fields
+	apply    func(c Client) error
+}
+
+// String implements the fmt.Stringer interface
+func (ch Change) String() string {
+	s := fmt.Sprintf("%s %s %q", ch.Action, ch.Resource, ch.Name)
+	if len(ch.Details) > 0 {
+		s += " (" + strings.Join(ch.Details, ", ") + ")"
+	}
+	return s
+}
+
+// Plan holds all the changes needed to reach the desired state,
+// an empty plan means that the company is already in sync
+type Plan struct {
+	Changes []Change
+}
+
+// Empty returns whether there is nothing to change
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Print writes the plan in a human readable way
+func (p Plan) Print(w io.Writer) error {
+	if p.Empty() {
+		_, err := fmt.Fprintln(w, "No changes, configuration is up to date.")
+		return err
+	}
+	for _, ch := range p.Changes {
+		if _, err := fmt.Fprintln(w, ch); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d change(s) to apply.\n", len(p.Changes))
+	return err
+}
+
+// Apply performs all the changes of the plan in order,
+// it stops on the first error
+func (p Plan) Apply(c Client) error {
+	for _, ch := range p.Changes {
+		if err := ch.apply(c); err != nil {
+			return fmt.Errorf("configsync: %s: %w", ch, err)
+		}
+	}
+	return nil
+}
+
+// Sync builds the plan for reach the desired state and applies it
+// unless DryRun is enabled. Running it again once applied produces an empty plan.
+func Sync(c Client, desired State, opts Options) (Plan, error) {
+	plan, err := Diff(c, desired, opts)
+	if err != nil {
+		return plan, err
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	return plan, plan.Apply(c)
+}
+
+// Diff compares the desired state with the current configuration
+// in Factorial and returns the plan needed for reach it
+func Diff(c Client, desired State, opts Options) (Plan, error) {
+	var plan Plan
+
+	if err := desired.validate(); err != nil {
+		return plan, err
+	}
+
+	leaveTypes, err := c.ListLeaveTypes()
+	if err != nil {
+		return plan, err
+	}
+	changes, err := diffLeaveTypes(leaveTypes, desired.LeaveTypes, opts.Prune)
+	if err != nil {
+		return plan, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+
+	folders, err := c.ListFolders(nil)
+	if err != nil {
+		return plan, err
+	}
+	plan.Changes = append(plan.Changes, diffFolders(folders, desired.Folders, opts.Prune)...)
+
+	webhooks, err := c.ListWebhooks()
+	if err != nil {
+		return plan, err
+	}
+	plan.Changes = append(plan.Changes, diffWebhooks(webhooks, desired.Webhooks, opts.Prune)...)
+
+	return plan, nil
+}
+
+func diffLeaveTypes(current []factorial.LeaveType, desired []LeaveType, prune bool) ([]Change, error) {
+	var changes []Change
+
+	byName := make(map[string]factorial.LeaveType)
+	for _, lt := range current {
+		byName[strings.ToLower(lt.Name)] = lt
+	}
+
+	declared := make(map[string]bool)
+	for _, d := range desired {
+		d := d
+		declared[strings.ToLower(d.Name)] = true
+
+		cur, ok := byName[strings.ToLower(d.Name)]
+		if !ok {
+			if !isActive(d.Active) {
+				continue
+			}
+			changes = append(changes, Change{
+				Resource: ResourceLeaveType,
+				Action:   ActionCreate,
+				Name:     d.Name,
+				apply: func(c Client) error {
+					_, err := c.CreateLeaveType(factorial.CreateLeaveTypeRequest{
+						Accrues:          boolValue(d.Accrues),
+						Active:           true,
+						ApprovalRequired: boolValue(d.ApprovalRequired),
+						Attachment:       boolValue(d.Attachment),
+						Color:            d.Color,
+						Name:             d.Name,
+						Visibility:       boolValue(d.Visibility),
+						Workable:         boolValue(d.Workable),
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		var req factorial.UpdateLeaveTypeRequest
+		var details []string
+		if d.Color != "" && d.Color != cur.Color {
+			req.Color = d.Color
+			details = append(details, fmt.Sprintf("color: %s -> %s", cur.Color, d.Color))
+		}
+		details = diffFlag("accrues", cur.Accrues, d.Accrues, &req.Accrues, details)
+		details = diffFlag("approval_required", cur.ApprovalRequired, d.ApprovalRequired, &req.ApprovalRequired, details)
+		details = diffFlag("attachment", cur.Attachment, d.Attachment, &req.Attachment, details)
+		details = diffFlag("visibility", cur.Visibility, d.Visibility, &req.Visibility, details)
+		details = diffFlag("workable", cur.Workable, d.Workable, &req.Workable, details)
+		details = diffFlag("active", cur.Active, factorial.Bool(isActive(d.Active)), &req.Active, details)
+		if len(details) == 0 {
+			continue
+		}
+		if !cur.IsCustom() {
+			return nil, fmt.Errorf("configsync: leave type %q is built-in and can't be modified", cur.Name)
+		}
+
+		action := ActionUpdate
+		if cur.Active && !isActive(d.Active) {
+			action = ActionDeactivate
+		}
+		changes = append(changes, updateLeaveTypeChange(cur, action, req, details))
+	}
+
+	if prune {
+		for _, cur := range current {
+			if declared[strings.ToLower(cur.Name)] || !cur.IsCustom() || !cur.Active {
+				continue
+			}
+			changes = append(changes, updateLeaveTypeChange(cur, ActionDeactivate, factorial.UpdateLeaveTypeRequest{
+				Active: factorial.Bool(false),
+			}, []string{"active: true -> false"}))
+		}
+	}
+
+	return changes, nil
+}
+
+func updateLeaveTypeChange(cur factorial.LeaveType, action string, req factorial.UpdateLeaveTypeRequest, details []string) Change {
+	return Change{
+		Resource: ResourceLeaveType,
+		Action:   action,
+		Name:     cur.Name,
+		Details:  details,
+		apply: func(c Client) error {
+			_, err := c.UpdateLeaveType(strconv.Itoa(cur.ID), req)
+			return err
+		},
+	}
+}
+
+func diffFolders(current []factorial.Folder, desired []Folder, prune bool) []Change {
+	var changes []Change
+
+	// Folders are identified by their path, so folders with
+	// the same name inside different parents are not mixed
+	tree := factorial.NewFolderTree(current)
+	paths := make(map[int]string)
+	byPath := make(map[string]factorial.Folder)
+	for _, f := range current {
+		if node, ok := tree.Get(f.ID); ok {
+			paths[f.ID] = node.Path()
+			byPath[strings.ToLower(node.Path())] = f
+		}
+	}
+
+	// Parents are created before their children
+	sorted := append([]Folder(nil), desired...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.Count(folderPath(sorted[i].Name), "/") < strings.Count(folderPath(sorted[j].Name), "/")
+	})
+
+	declared := make(map[string]bool)
+	for _, d := range sorted {
+		path := folderPath(d.Name)
+		declared[strings.ToLower(path)] = true
+		active := isActive(d.Active)
+
+		cur, ok := byPath[strings.ToLower(path)]
+		if !ok {
+			if !active {
+				continue
+			}
+			changes = append(changes, Change{
+				Resource: ResourceFolder,
+				Action:   ActionCreate,
+				Name:     path,
+				apply:    createFolder(path),
+			})
+			continue
+		}
+		if cur.Active == active {
+			continue
+		}
+
+		action := ActionUpdate
+		if !active {
+			action = ActionDeactivate
+		}
+		changes = append(changes, updateFolderChange(cur, paths[cur.ID], action, active))
+	}
+
+	if prune {
+		for _, cur := range current {
+			if cur.Type != factorial.FolderTypeCustom || declared[strings.ToLower(paths[cur.ID])] || !cur.Active {
+				continue
+			}
+			changes = append(changes, updateFolderChange(cur, paths[cur.ID], ActionDeactivate, false))
+		}
+	}
+
+	return changes
+}
+
+// createFolder creates the folder of the path inside its parent,
+// the parent must exist when the change is applied
+func createFolder(path string) func(c Client) error {
+	return func(c Client) error {
+		var parentID int
+		i := strings.LastIndex(path, "/")
+		if i >= 0 {
+			folders, err := c.ListFolders(nil)
+			if err != nil {
+				return err
+			}
+			parent, err := factorial.NewFolderTree(folders).Find(path[:i])
+			if err != nil {
+				return fmt.Errorf("parent of %q: %w", path, err)
+			}
+			parentID = parent.ID
+		}
+
+		_, err := c.CreateFolder(factorial.CreateFolderRequest{Name: path[i+1:], Active: true, ParentID: parentID})
+		return err
+	}
+}
+
+func updateFolderChange(cur factorial.Folder, path, action string, active bool) Change {
+	return Change{
+		Resource: ResourceFolder,
+		Action:   action,
+		Name:     path,
+		Details:  []string{fmt.Sprintf("active: %t -> %t", cur.Active, active)},
+		apply: func(c Client) error {
+			_, err := c.UpdateFolder(strconv.Itoa(cur.ID), factorial.UpdateFolderRequest{
+				Name:   cur.Name,
+				Active: active,
+			})
+			return err
+		},
+	}
+}
+
+// folderPath returns the path of the folder without
+// empty names, like "Contracts/2024"
+func folderPath(name string) string {
+	var names []string
+	for _, n := range strings.Split(name, "/") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+func diffWebhooks(current []factorial.Webhook, desired []Webhook, prune bool) []Change {
+	var changes []Change
+
+	byType := make(map[string]factorial.Webhook)
+	for _, w := range current {
+		byType[w.SubscriptionType] = w
+	}
+
+	declared := make(map[string]bool)
+	for _, d := range desired {
+		declared[d.SubscriptionType] = true
+
+		req := factorial.CreateWebhookRequest{
+			SubscriptionType: d.SubscriptionType,
+			TargetURL:        d.TargetURL,
+		}
+		// Creating an existing webhook just changes its target url
+		apply := func(c Client) error {
+			_, err := c.CreateWebhook(req)
+			return err
+		}
+
+		cur, ok := byType[d.SubscriptionType]
+		switch {
+		case !ok:
+			changes = append(changes, Change{
+				Resource: ResourceWebhook,
+				Action:   ActionCreate,
+				Name:     d.SubscriptionType,
+				Details:  []string{"target_url: " + d.TargetURL},
+				apply:    apply,
+			})
+		case cur.TargetURL != d.TargetURL:
+			changes = append(changes, Change{
+				Resource: ResourceWebhook,
+				Action:   ActionUpdate,
+				Name:     d.SubscriptionType,
+				Details:  []string{fmt.Sprintf("target_url: %s -> %s", cur.TargetURL, d.TargetURL)},
+				apply:    apply,
+			})
+		}
+	}
+
+	if prune {
+		for _, cur := range current {
+			if declared[cur.SubscriptionType] {
+				continue
+			}
+			req := factorial.DeleteWebhookRequest{SubscriptionType: cur.SubscriptionType}
+			changes = append(changes, Change{
+				Resource: ResourceWebhook,
+				Action:   ActionDeactivate,
+				Name:     cur.SubscriptionType,
+				apply: func(c Client) error {
+					_, err := c.DeleteWebhook(req)
+					return err
+				},
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffFlag sets the request flag when the desired value differs
+// from the current one, returning the details with the change added
+func diffFlag(name string, current bool, desired *bool, req **bool, details []string) []string {
+	if desired == nil || *desired == current {
+		return details
+	}
+	*req = factorial.Bool(*desired)
+	return append(details, fmt.Sprintf("%s: %t -> %t", name, current, *desired))
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
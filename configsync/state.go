@@ -0,0 +1,144 @@
+// Package configsync keeps the company configuration of Factorial
+// (leave types, folders and webhooks) in sync with a desired state
+// defined as code in a YAML or JSON file.
+package configsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Supported formats for the desired state
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// State is the desired configuration of the company.
+// Only the declared items are managed, the rest are kept
+// as they are unless Prune is enabled on the Options.
+type State struct {
+	LeaveTypes []LeaveType `json:"leave_types" yaml:"leave_types"`
+	Folders    []Folder    `json:"folders" yaml:"folders"`
+	Webhooks   []Webhook   `json:"webhooks" yaml:"webhooks"`
+}
+
+// LeaveType is the desired state of a leave type, identified by its name.
+// Nil flags are not managed and keep the value they have in Factorial.
+type LeaveType struct {
+	Name             string `json:"name" yaml:"name"`
+	Color            string `json:"color" yaml:"color"`
+	Active           *bool  `json:"active" yaml:"active"` // Active by default
+	Accrues          *bool  `json:"accrues" yaml:"accrues"`
+	ApprovalRequired *bool  `json:"approval_required" yaml:"approval_required"`
+	Attachment       *bool  `json:"attachment" yaml:"attachment"`
+	Visibility       *bool  `json:"visibility" yaml:"visibility"`
+	Workable         *bool  `json:"workable" yaml:"workable"`
+}
+
+// Folder is the desired state of a folder, identified by its name
+type Folder struct {
+	Name   string `json:"name" yaml:"name"`     // Path of the folder, like "Contracts/2024"
+	Active *bool  `json:"active" yaml:"active"` // Active by default
+}
+
+// Webhook is the desired state of a webhook subscription,
+// identified by its subscription type
+type Webhook struct {
+	SubscriptionType string `json:"subscription_type" yaml:"subscription_type"`
+	TargetURL        string `json:"target_url" yaml:"target_url"`
+}
+
+// Load reads the desired state from the given reader
+// in the given format, FormatJSON or FormatYAML
+func Load(r io.Reader, format string) (State, error) {
+	var state State
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return state, err
+	}
+
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &state)
+	case FormatYAML:
+		err = yaml.UnmarshalStrict(data, &state)
+	default:
+		return state, fmt.Errorf("configsync: unsupported format %q", format)
+	}
+	if err != nil {
+		return state, err
+	}
+
+	return state, state.validate()
+}
+
+// LoadFile reads the desired state from the given file,
+// the format is chosen from the file extension
+func LoadFile(path string) (State, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return State{}, err
+	}
+	defer f.Close()
+
+	format := FormatJSON
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	}
+
+	return Load(f, format)
+}
+
+// validate checks that every item can be identified
+// and that no item is declared twice
+func (s State) validate() error {
+	leaveTypes := make(map[string]bool)
+	for _, lt := range s.LeaveTypes {
+		if lt.Name == "" {
+			return fmt.Errorf("configsync: leave type without name")
+		}
+		if leaveTypes[strings.ToLower(lt.Name)] {
+			return fmt.Errorf("configsync: leave type %q declared twice", lt.Name)
+		}
+		leaveTypes[strings.ToLower(lt.Name)] = true
+	}
+
+	folders := make(map[string]bool)
+	for _, f := range s.Folders {
+		path := folderPath(f.Name)
+		if path == "" {
+			return fmt.Errorf("configsync: folder without name")
+		}
+		if folders[strings.ToLower(path)] {
+			return fmt.Errorf("configsync: folder %q declared twice", f.Name)
+		}
+		folders[strings.ToLower(path)] = true
+	}
+
+	webhooks := make(map[string]bool)
+	for _, w := range s.Webhooks {
+		if w.SubscriptionType == "" || w.TargetURL == "" {
+			return fmt.Errorf("configsync: webhook needs subscription_type and target_url")
+		}
+		if webhooks[w.SubscriptionType] {
+			return fmt.Errorf("configsync: webhook %q declared twice", w.SubscriptionType)
+		}
+		webhooks[w.SubscriptionType] = true
+	}
+
+	return nil
+}
+
+func isActive(active *bool) bool {
+	return active == nil || *active
+}
@@ -0,0 +1,211 @@
+// Package docsync mirrors the documents stored in Factorial into a local
+// directory, as folder/employee/filename, for backup and legal-hold purposes.
+package docsync
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/pathname"
+)
+
+var _ Client = factorial.Client{}
+
+// Client is the subset of the factorial.Client used for mirror the documents
+type Client interface {
+	ListFolders(filter url.Values) ([]factorial.Folder, error)
+	ListDocuments(filter url.Values) ([]factorial.Document, error)
+	ListEmployees() ([]factorial.Employee, error)
+	DownloadDocumentTo(ctx context.Context, id, path string) (factorial.DocumentMetadata, error)
+}
+
+// Options changes how the documents are mirrored
+type Options struct {
+	// Delete removes the local files of the documents deleted in Factorial
+	Delete bool
+	// Verify recomputes the checksum of the files already mirrored
+	// and downloads again the ones that don't match the manifest
+	Verify bool
+}
+
+// Report holds what happened on a sync
+type Report struct {
+	Downloaded []string // Local paths written
+	Skipped    int      // Documents already up to date
+	Deleted    []string // Local paths removed
+	Failed     map[int]error
+}
+
+// Sync mirrors all the documents into dir, only downloading the ones new or
+// updated since the last sync according to the manifest kept in dir. The
+// manifest is saved after each document, so an interrupted sync can be run
+// again and continues where it stopped.
+func Sync(ctx context.Context, c Client, dir string, opts Options) (Report, error) {
+	report := Report{Failed: make(map[int]error)}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return report, err
+	}
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return report, err
+	}
+
+	folders, err := c.ListFolders(nil)
+	if err != nil {
+		return report, err
+	}
+	employees, err := c.ListEmployees()
+	if err != nil {
+		return report, err
+	}
+	documents, err := c.ListDocuments(nil)
+	if err != nil {
+		return report, err
+	}
+	sort.Slice(documents, func(i, j int) bool { return documents[i].ID < documents[j].ID })
+
+	paths := documentPaths(documents, folders, employees, manifest)
+	remote := make(map[int]bool)
+	for _, d := range documents {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		remote[d.ID] = true
+		rel := paths[d.ID]
+
+		entry, ok := manifest.Documents[d.ID]
+		if ok && entry.UpdatedAt == d.UpdatedAt && entry.Path == rel && upToDate(dir, entry, opts.Verify) {
+			report.Skipped++
+			continue
+		}
+		if ok && entry.Path != rel && !manifest.ownedByOther(d.ID, entry.Path) {
+			// The document was moved or renamed, the old copy is replaced
+			os.Remove(filepath.Join(dir, filepath.FromSlash(entry.Path)))
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return report, err
+		}
+		if _, err := c.DownloadDocumentTo(ctx, strconv.Itoa(d.ID), path); err != nil {
+			report.Failed[d.ID] = err
+			continue
+		}
+		sum, size, err := checksum(path)
+		if err != nil {
+			return report, err
+		}
+		manifest.Documents[d.ID] = Entry{
+			Path:      rel,
+			UpdatedAt: d.UpdatedAt,
+			SHA256:    sum,
+			Size:      size,
+		}
+		if err := manifest.Save(dir); err != nil {
+			return report, err
+		}
+		report.Downloaded = append(report.Downloaded, path)
+	}
+
+	if opts.Delete {
+		for id, entry := range manifest.Documents {
+			if remote[id] {
+				continue
+			}
+			path := filepath.Join(dir, filepath.FromSlash(entry.Path))
+			if manifest.ownedByOther(id, entry.Path) {
+				// The path was given to another document, only the entry goes
+				delete(manifest.Documents, id)
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				report.Failed[id] = err
+				continue
+			}
+			delete(manifest.Documents, id)
+			report.Deleted = append(report.Deleted, path)
+		}
+		if err := manifest.Save(dir); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// upToDate returns whether the local copy of the entry exists with
+// the expected size, and the expected checksum when verify is enabled
+func upToDate(dir string, entry Entry, verify bool) bool {
+	path := filepath.Join(dir, filepath.FromSlash(entry.Path))
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != entry.Size {
+		return false
+	}
+	if !verify {
+		return true
+	}
+	sum, _, err := checksum(path)
+	return err == nil && sum == entry.SHA256
+}
+
+// documentPaths returns the relative path, with slashes, of every
+// document as folder/employee/filename. Documents with the same path as
+// another document, or as the local copy of another document on the
+// manifest, get their id appended to the file name.
+func documentPaths(documents []factorial.Document, folders []factorial.Folder, employees []factorial.Employee, manifest Manifest) map[int]string {
+	folderNames := make(map[int]string)
+	for _, f := range folders {
+		folderNames[f.ID] = f.Name
+	}
+	employeeNames := make(map[int]string)
+	for _, e := range employees {
+		name := e.FullName
+		if name == "" {
+			name = strings.TrimSpace(e.FirstName + " " + e.LastName)
+		}
+		employeeNames[e.ID] = fmt.Sprintf("%s (%d)", name, e.ID)
+	}
+
+	// Paths kept on disk for the documents of the manifest
+	kept := make(map[string]int)
+	for id, entry := range manifest.Documents {
+		kept[strings.ToLower(entry.Path)] = id
+	}
+
+	paths := make(map[int]string)
+	used := make(map[string]bool)
+	for _, d := range documents {
+		folder := folderNames[d.FolderID]
+		if folder == "" {
+			folder = "No folder"
+		}
+		employee := "Company"
+		if d.EmployeeID != 0 {
+			employee = employeeNames[d.EmployeeID]
+			if employee == "" {
+				employee = fmt.Sprintf("Employee %d", d.EmployeeID)
+			}
+		}
+		name := d.FileName
+		if name == "" {
+			name = fmt.Sprintf("document-%d", d.ID)
+		}
+
+		path := pathname.Sanitize(folder) + "/" + pathname.Sanitize(employee) + "/" + pathname.Sanitize(name)
+		if id, ok := kept[strings.ToLower(path)]; used[strings.ToLower(path)] || (ok && id != d.ID) {
+			ext := filepath.Ext(name)
+			path = pathname.Sanitize(folder) + "/" + pathname.Sanitize(employee) + "/" + pathname.Sanitize(fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), d.ID, ext))
+		}
+		used[strings.ToLower(path)] = true
+		paths[d.ID] = path
+	}
+	return paths
+}
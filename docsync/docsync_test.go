@@ -0,0 +1,112 @@
+package docsync
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/arexio/factorial-go"
+)
+
+// fakeClient serves the documents, the content of each
+// downloaded file is the id of its document
+type fakeClient struct {
+	documents []factorial.Document
+}
+
+func (f *fakeClient) ListFolders(filter url.Values) ([]factorial.Folder, error) {
+	return []factorial.Folder{{ID: 1, Name: "Contracts"}}, nil
+}
+
+func (f *fakeClient) ListDocuments(filter url.Values) ([]factorial.Document, error) {
+	return f.documents, nil
+}
+
+func (f *fakeClient) ListEmployees() ([]factorial.Employee, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) DownloadDocumentTo(ctx context.Context, id, path string) (factorial.DocumentMetadata, error) {
+	return factorial.DocumentMetadata{}, ioutil.WriteFile(path, []byte(id), 0644)
+}
+
+func TestSyncKeepsPathsOfManifest(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete bool
+		first  []int // Documents on the first sync, all named contract.pdf
+		second []int // Documents on the second sync
+		want   map[string]string
+	}{
+		{
+			name:   "retained copy not overwritten",
+			first:  []int{3},
+			second: []int{7},
+			want:   map[string]string{"contract.pdf": "3", "contract-7.pdf": "7"},
+		},
+		{
+			name:   "renamed copy not deleted",
+			delete: true,
+			first:  []int{3, 5},
+			second: []int{5},
+			want:   map[string]string{"contract-5.pdf": "5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "docsync")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			c := &fakeClient{}
+			for _, ids := range [][]int{tt.first, tt.second} {
+				c.documents = nil
+				for _, id := range ids {
+					c.documents = append(c.documents, factorial.Document{ID: id, FolderID: 1, FileName: "contract.pdf", UpdatedAt: "1"})
+				}
+				if _, err := Sync(context.Background(), c, dir, Options{Delete: tt.delete}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			files, err := ioutil.ReadDir(filepath.Join(dir, "Contracts", "Company"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, f := range files {
+				b, err := ioutil.ReadFile(filepath.Join(dir, "Contracts", "Company", f.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[f.Name()] = string(b)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+			for name, id := range tt.want {
+				if got[name] != id {
+					t.Errorf("%s = %q, want document %s", name, got[name], id)
+				}
+			}
+
+			manifest, err := LoadManifest(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range tt.second {
+				entry := manifest.Documents[id]
+				if got[filepath.Base(entry.Path)] != strconv.Itoa(id) {
+					t.Errorf("manifest path of %d = %q, holds %q", id, entry.Path, got[filepath.Base(entry.Path)])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package docsync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFile is the name of the manifest kept on the mirrored directory
+const ManifestFile = ".factorial-manifest.json"
+
+// Entry is a mirrored document on the manifest
+type Entry struct {
+	Path      string `json:"path"` // Relative to the mirrored directory, with slashes
+	UpdatedAt string `json:"updated_at"`
+	SHA256    string `json:"sha256"`
+	Size      int64  `json:"size"`
+}
+
+// Manifest keeps the documents mirrored by their id
+type Manifest struct {
+	Documents map[int]Entry `json:"documents"`
+}
+
+// LoadManifest reads the manifest of the given directory,
+// an empty one is returned if it doesn't exist yet
+func LoadManifest(dir string) (Manifest, error) {
+	m := Manifest{Documents: make(map[int]Entry)}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, err
+	}
+	if m.Documents == nil {
+		m.Documents = make(map[int]Entry)
+	}
+
+	return m, nil
+}
+
+// Save writes the manifest into the given directory, the file
+// is replaced atomically so it's never left half written
+func (m Manifest) Save(dir string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, ManifestFile))
+}
+
+// ownedByOther returns whether the path belongs to the entry
+// of a document other than the given one
+func (m Manifest) ownedByOther(id int, path string) bool {
+	for other, entry := range m.Documents {
+		if other != id && strings.EqualFold(entry.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// checksum returns the SHA-256 and the size of the given file
+func checksum(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
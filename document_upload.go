@@ -0,0 +1,155 @@
+package factorial
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// MaxDocumentSize is the default maximum size in bytes of an uploaded document
+const MaxDocumentSize = 25 << 20
+
+// ErrDocumentTooLarge is returned when the uploaded document exceeds its maximum size
+var ErrDocumentTooLarge = errors.New("factorial: document exceeds the maximum allowed size")
+
+// UploadDocumentRequest will hold the information needed for
+// create a new document in Factorial from a reader
+type UploadDocumentRequest struct {
+	Public            bool
+	EmployeeID        int
+	FolderID          int
+	RequestESignature bool
+	Signees           []int
+	File              io.Reader
+	FileName          string
+	ContentType       string           // Detected from the content and the file name if empty
+	MaxSize           int64            // MaxDocumentSize if zero
+	Progress          func(read int64) // Called with the bytes read from File so far
+}
+
+// UploadDocument creates a new document in Factorial reading its content from the
+// given reader. The content is base64 encoded while it's sent, so memory stays
+// bounded for large files. Files bigger than MaxSize fail with ErrDocumentTooLarge.
+func (c Client) UploadDocument(d UploadDocumentRequest) (Document, error) {
+	var document Document
+
+	maxSize := d.MaxSize
+	if maxSize == 0 {
+		maxSize = MaxDocumentSize
+	}
+	file := bufio.NewReaderSize(d.File, 512)
+	contentType := d.ContentType
+	if contentType == "" {
+		contentType = detectContentType(file, d.FileName)
+	}
+
+	// Body is the same than CreateDocumentRequest with the file streamed as data URI
+	meta, err := json.Marshal(struct {
+		Public            bool   `json:"public"`
+		EmployeeID        int    `json:"employee_id"`
+		FileName          string `json:"filename"`
+		FolderID          int    `json:"folder_id"`
+		RequestESignature bool   `json:"request_esignature"`
+		Signees           []int  `json:"signees"`
+	}{d.Public, d.EmployeeID, d.FileName, d.FolderID, d.RequestESignature, d.Signees})
+	if err != nil {
+		return document, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeDocumentBody(pw, meta, contentType, &countingReader{
+			r:        file,
+			max:      maxSize,
+			progress: d.Progress,
+		}))
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, c.apiURL+documentURL, pr)
+	if err != nil {
+		pr.Close()
+		return document, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrDocumentTooLarge) {
+			return document, ErrDocumentTooLarge
+		}
+		return document, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return document, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return document, err
+	}
+
+	return document, nil
+}
+
+// writeDocumentBody writes the JSON metadata adding the file field
+// with the content of the reader encoded as a base64 data URI
+func writeDocumentBody(w io.Writer, meta []byte, contentType string, r io.Reader) error {
+	prefix := string(meta[:len(meta)-1]) + `,"file":"data:` + contentType + `;base64,`
+	if _, err := io.WriteString(w, prefix); err != nil {
+		return err
+	}
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := io.Copy(enc, r); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, `"}`)
+	return err
+}
+
+// detectContentType sniffs the first bytes of the content, falling back
+// to the file extension when the content is not recognised
+func detectContentType(r *bufio.Reader, fileName string) string {
+	head, _ := r.Peek(512)
+	contentType := http.DetectContentType(head)
+	if strings.HasPrefix(contentType, "application/octet-stream") || strings.HasPrefix(contentType, "text/plain") {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			contentType = byExt
+		}
+	}
+	// Parameters like the charset are not valid inside the data URI
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// countingReader reports the bytes read and fails
+// with ErrDocumentTooLarge once max is exceeded
+type countingReader struct {
+	r        io.Reader
+	read     int64
+	max      int64
+	progress func(int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += int64(n)
+	if c.read > c.max {
+		return n, ErrDocumentTooLarge
+	}
+	if n > 0 && c.progress != nil {
+		c.progress(c.read)
+	}
+	return n, err
+}
@@ -1,8 +1,14 @@
 package factorial
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"io"
 	"net/url"
+	"os"
 )
 
 const (
@@ -45,6 +51,16 @@ type UpdateDocumentRequest struct {
 	Signees           []int `json:"signees"`
 }
 
+// DocumentFile encodes the given content as a base64 data URI, the value
+// expected by CreateDocumentRequest.File. The content type is detected
+// from the content if empty.
+func DocumentFile(content []byte, contentType string) string {
+	if contentType == "" {
+		contentType = detectContentType(bufio.NewReader(bytes.NewReader(content)), "")
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content)
+}
+
 // CreateDocument creates a new document in Factorial
 func (c Client) CreateDocument(d CreateDocumentRequest) (Document, error) {
 	var document Document
@@ -58,6 +74,9 @@ func (c Client) CreateDocument(d CreateDocumentRequest) (Document, error) {
 	if err != nil {
 		return document, err
 	}
+	if err := checkResponse(resp); err != nil {
+		return document, err
+	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
 		return document, err
@@ -132,3 +151,79 @@ func (c Client) UpdateDocument(id string, d UpdateDocumentRequest) (Document, er
 
 	return document, nil
 }
+
+// DocumentMetadata holds the information of a downloaded document
+type DocumentMetadata struct {
+	Document      Document
+	ContentType   string
+	ContentLength int64 // -1 when unknown
+	ETag          string
+}
+
+// DownloadDocument gets the content of the given document id through the
+// authenticated client. The content type is detected from the content when
+// the server doesn't send it. The caller must close the returned reader.
+func (c Client) DownloadDocument(ctx context.Context, id string) (io.ReadCloser, DocumentMetadata, error) {
+	meta := DocumentMetadata{ContentLength: -1}
+
+	document, err := c.GetDocument(id)
+	if err != nil {
+		return nil, meta, err
+	}
+	meta.Document = document
+
+	resp, err := c.downloadRequest(ctx, c.resolveFileURL(document.File, documentURL+"/"+id+"/download"), 0, "")
+	if err != nil {
+		return nil, meta, err
+	}
+	meta.ContentLength = resp.ContentLength
+	meta.ETag = resp.Header.Get("ETag")
+	meta.ContentType = resp.Header.Get("Content-Type")
+
+	body := bufio.NewReader(resp.Body)
+	if meta.ContentType == "" || meta.ContentType == "application/octet-stream" {
+		meta.ContentType = detectContentType(body, document.FileName)
+	}
+
+	return readCloser{Reader: body, Closer: resp.Body}, meta, nil
+}
+
+// DownloadDocumentTo writes the content of the given document id into path.
+// Interrupted downloads are resumed from the "<path>.part" file on the next
+// call, and the content is verified with the checksum sent by the server.
+func (c Client) DownloadDocumentTo(ctx context.Context, id, path string) (DocumentMetadata, error) {
+	meta := DocumentMetadata{ContentLength: -1}
+
+	document, err := c.GetDocument(id)
+	if err != nil {
+		return meta, err
+	}
+	meta.Document = document
+
+	resp, err := c.downloadTo(ctx, c.resolveFileURL(document.File, documentURL+"/"+id+"/download"), path)
+	if err != nil {
+		return meta, err
+	}
+	meta.ETag = resp.Header.Get("ETag")
+	meta.ContentType = resp.Header.Get("Content-Type")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return meta, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil {
+		meta.ContentLength = info.Size()
+	}
+	if meta.ContentType == "" || meta.ContentType == "application/octet-stream" {
+		meta.ContentType = detectContentType(bufio.NewReader(f), document.FileName)
+	}
+
+	return meta, nil
+}
+
+// readCloser joins a reader with the closer of the underlying body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,196 @@
+package factorial
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned when the downloaded content
+// doesn't match the checksum sent by the server
+var ErrChecksumMismatch = errors.New("factorial: downloaded file checksum mismatch")
+
+// downloadRequest gets the given url starting at offset, use the If-Range
+// etag for only resume when the file didn't change. Urls on the API host
+// use the authenticated client, others (like signed storage urls) don't
+// receive our credentials.
+func (c Client) downloadRequest(ctx context.Context, rawURL string, offset int64, etag string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		if etag != "" {
+			req.Header.Set("If-Range", etag)
+		}
+	}
+
+	client := http.DefaultClient
+	if c.isAPIURL(req.URL) && c.Client != nil {
+		client = c.Client
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// isRangeNotSatisfiable returns whether the error is
+// a 416 response to a range request
+func isRangeNotSatisfiable(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable
+}
+
+// isAPIURL returns whether the given url belongs to the API host
+func (c Client) isAPIURL(u *url.URL) bool {
+	api, err := url.Parse(c.apiURL)
+	return err == nil && strings.EqualFold(api.Host, u.Host)
+}
+
+// resolveFileURL returns the absolute url of a file reference, relative
+// references are resolved against the API, empty ones use the fallback endpoint
+func (c Client) resolveFileURL(file, fallback string) string {
+	switch {
+	case strings.HasPrefix(file, "http://"), strings.HasPrefix(file, "https://"):
+		return file
+	case strings.HasPrefix(file, "/"):
+		return c.apiURL + file
+	default:
+		return c.apiURL + fallback
+	}
+}
+
+// downloadTo writes the content of the url into path, resuming from the
+// "<path>.part" file left by a previous interrupted call. Parts are only
+// resumed when the server sent an etag for them, so the rest of the file
+// is requested with If-Range and a changed file is downloaded again.
+// The content is verified with the Content-MD5 header or the MD5 etag when available.
+func (c Client) downloadTo(ctx context.Context, rawURL, path string) (*http.Response, error) {
+	part := path + ".part"
+	etagFile := part + ".etag"
+
+	var offset int64
+	var etag string
+	if info, err := os.Stat(part); err == nil {
+		if b, err := ioutil.ReadFile(etagFile); err == nil && len(b) > 0 {
+			offset, etag = info.Size(), string(b)
+		}
+	}
+
+	resp, err := c.downloadRequest(ctx, rawURL, offset, etag)
+	if offset > 0 && isRangeNotSatisfiable(err) {
+		// The part is already complete, or bigger than the file now, so
+		// it can't be resumed and the whole file is downloaded again
+		etag = ""
+		resp, err = c.downloadRequest(ctx, rawURL, 0, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusPartialContent && resp.Header.Get("ETag") != etag {
+		// The range doesn't belong to the file of the part
+		resp.Body.Close()
+		resp, err = c.downloadRequest(ctx, rawURL, 0, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if resp.StatusCode != http.StatusPartialContent {
+		// The server sent the whole file, the partial one is discarded
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	if e := resp.Header.Get("ETag"); e != "" {
+		if err := ioutil.WriteFile(etagFile, []byte(e), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := verifyChecksum(part, resp); err != nil {
+		os.Remove(part)
+		os.Remove(etagFile)
+		return nil, err
+	}
+	os.Remove(etagFile)
+
+	return resp, os.Rename(part, path)
+}
+
+// verifyChecksum compares the MD5 of the file with the one sent by the server,
+// if any, on the Content-MD5 header or as a plain etag. Content-MD5 is ignored
+// on partial responses because it only covers the range sent.
+func verifyChecksum(path string, resp *http.Response) error {
+	var expected string
+	h := resp.Header
+	if sum := h.Get("Content-MD5"); sum != "" && resp.StatusCode != http.StatusPartialContent {
+		b, err := base64.StdEncoding.DecodeString(sum)
+		if err != nil {
+			return fmt.Errorf("factorial: invalid Content-MD5 %q", sum)
+		}
+		expected = hex.EncodeToString(b)
+	} else if etag := strings.Trim(h.Get("ETag"), `"`); len(etag) == 32 && isHex(etag) {
+		expected = strings.ToLower(etag)
+	}
+	if expected == "" {
+		return nil
+	}
+
+	sum, err := fileMD5(path)
+	if err != nil {
+		return err
+	}
+	if sum != expected {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
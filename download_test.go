@@ -0,0 +1,105 @@
+package factorial
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// rangeServer serves content honouring Range and If-Range like a storage server,
+// answering 416 to ranges starting at or after the end of the content
+func rangeServer(t *testing.T, content, etag string) (*httptest.Server, *[]string) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		rng := r.Header.Get("Range")
+		if rng == "" || (r.Header.Get("If-Range") != "" && r.Header.Get("If-Range") != etag) {
+			w.Write([]byte(content))
+			return
+		}
+		offset, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil {
+			t.Fatalf("invalid range %q", rng)
+		}
+		if offset >= len(content) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(content[offset:]))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &ranges
+}
+
+func md5Etag(content string) string {
+	sum := md5.Sum([]byte(content))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func TestDownloadToResume(t *testing.T) {
+	const content = "0123456789abcdef"
+
+	tests := []struct {
+		name       string
+		etag       string // Sent by the server
+		part       string // Left by a previous run
+		partEtag   string // Saved with the part, none if empty
+		wantRanges []string
+	}{
+		{"no part", md5Etag(content), "", "", []string{""}},
+		{"resumes part with etag", md5Etag(content), "0123", md5Etag(content), []string{"bytes=4-"}},
+		{"complete part restarts", md5Etag(content), content, md5Etag(content), []string{"bytes=16-", ""}},
+		{"part without etag restarts", "", "0123", "", []string{""}},
+		{"changed file restarts", md5Etag(content), "xxxx", `"old"`, []string{"bytes=4-"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, ranges := rangeServer(t, content, tt.etag)
+			c := Client{Client: srv.Client(), apiURL: srv.URL}
+
+			dir, err := ioutil.TempDir("", "download")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "file")
+			if tt.part != "" {
+				if err := ioutil.WriteFile(path+".part", []byte(tt.part), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.partEtag != "" {
+				if err := ioutil.WriteFile(path+".part.etag", []byte(tt.partEtag), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := c.downloadTo(context.Background(), srv.URL+"/file", path); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != content {
+				t.Errorf("content = %q, want %q", got, content)
+			}
+			if strings.Join(*ranges, ",") != strings.Join(tt.wantRanges, ",") {
+				t.Errorf("ranges = %q, want %q", *ranges, tt.wantRanges)
+			}
+		})
+	}
+}
@@ -90,6 +90,9 @@ func (c Client) GetEmployee(id string) (Employee, error) {
 	if err != nil {
 		return employee, err
 	}
+	if err := checkResponse(resp); err != nil {
+		return employee, err
+	}
 	defer resp.Body.Close()
 
 	if err := json.NewDecoder(resp.Body).Decode(&employee); err != nil {
@@ -501,7 +501,7 @@ func ClockInHandler(w http.ResponseWriter, r *http.Request) {
 
 	req := factorial.ClockInRequest{
 		EmployeeID: employeeID,
-		Now:        time.Now().Format("2006-01-02T15:04:05-0700"),
+		Now:        time.Now(),
 	}
 	shift, err := cl.ClockIn(req)
 	if err != nil {
@@ -547,7 +547,7 @@ func ClockOutHandler(w http.ResponseWriter, r *http.Request) {
 
 	req := factorial.ClockOutRequest{
 		EmployeeID: employeeID,
-		Now:        time.Now().Format("2006-01-02T15:04:05-0700"),
+		Now:        time.Now(),
 	}
 	shift, err := cl.ClockOut(req)
 	if err != nil {
@@ -2,17 +2,25 @@ package factorial
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const factorialAPI = "https://api.factorialhr.com"
 
+// DateLayout is the layout used by Factorial for dates
+const DateLayout = "2006-01-02"
+
 // New builds a Factorial client from the provided accessToken and options.
 func New(opts ...Option) (*Client, error) {
 	c := &Client{
-		apiURL: factorialAPI,
+		apiURL:       factorialAPI,
+		toggleWindow: defaultToggleWindow,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -36,13 +44,63 @@ func WithAPIURL(url string) func(*Client) {
 	}
 }
 
+// Bool returns a pointer to the given value, useful
+// for the optional flags of the update requests.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// WithToggleWindow sets the window used by ToggleClock for ignore
+// duplicated taps, zero disables the protection.
+func WithToggleWindow(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.toggleWindow = d
+	}
+}
+
 // Option defines an option for a Client.
 type Option func(*Client)
 
 // Client for the Factorial API.
 type Client struct {
 	*http.Client
-	apiURL string
+	apiURL       string
+	toggleWindow time.Duration
+}
+
+// APIError is returned when Factorial answers with
+// a non successful status code
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e APIError) Error() string {
+	return fmt.Sprintf("factorial: unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// IsUnprocessable returns whether the error is an APIError
+// with status code 422, returned by Factorial when the request
+// is not valid for the current state, like clock in twice
+func IsUnprocessable(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity
+}
+
+// checkResponse returns an APIError if the given response
+// has a non successful status code, closing its body
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	return APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
 }
 
 func (c Client) delete(endpoint string) (*http.Response, error) {
@@ -0,0 +1,197 @@
+package factorial
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrFolderNotFound is returned when no folder matches the given path
+var ErrFolderNotFound = errors.New("factorial: folder not found")
+
+var errNoFolderTreeClient = errors.New("factorial: folder tree built without client")
+
+// FolderNode is a folder inside a FolderTree
+type FolderNode struct {
+	Folder
+	Parent   *FolderNode
+	Children []*FolderNode
+}
+
+// Path returns the names of the folder and its parents joined by "/"
+func (n *FolderNode) Path() string {
+	if n.Parent == nil {
+		return n.Name
+	}
+	return n.Parent.Path() + "/" + n.Name
+}
+
+// child returns the direct child with the given name, case insensitive
+func (n *FolderNode) child(name string) *FolderNode {
+	for _, c := range n.Children {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// FolderTree is the hierarchy of the company folders,
+// it allows resolving folders and documents by path
+type FolderTree struct {
+	client Client
+	root   *FolderNode // Virtual node holding the root folders
+	byID   map[int]*FolderNode
+}
+
+// FolderTree builds the hierarchy from all the folders of your company
+func (c Client) FolderTree() (*FolderTree, error) {
+	folders, err := c.ListFolders(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewFolderTree(folders)
+	t.client = c
+	return t, nil
+}
+
+// NewFolderTree builds the hierarchy from the given folders, folders whose
+// parent is not in the list or that are their own ancestor are placed at
+// the root. A tree built this way can't create folders nor list documents.
+func NewFolderTree(folders []Folder) *FolderTree {
+	t := &FolderTree{
+		root: &FolderNode{},
+		byID: make(map[int]*FolderNode),
+	}
+	parents := make(map[int]int)
+	for _, f := range folders {
+		t.byID[f.ID] = &FolderNode{Folder: f}
+		parents[f.ID] = f.ParentID
+	}
+	for _, f := range folders {
+		node := t.byID[f.ID]
+		parent, ok := t.byID[f.ParentID]
+		if !ok || isOwnAncestor(parents, f.ID) {
+			parent = t.root
+		}
+		t.attach(parent, node)
+	}
+	return t
+}
+
+// isOwnAncestor returns whether following the parents of the folder
+// leads back to it, like A inside B inside A
+func isOwnAncestor(parents map[int]int, id int) bool {
+	seen := make(map[int]bool)
+	for parent, ok := parents[id]; ok && !seen[parent]; parent, ok = parents[parent] {
+		if parent == id {
+			return true
+		}
+		seen[parent] = true
+	}
+	return false
+}
+
+// Roots returns the folders at the top of the hierarchy
+func (t *FolderTree) Roots() []*FolderNode {
+	return t.root.Children
+}
+
+// Get returns the folder with the given id
+func (t *FolderTree) Get(id int) (*FolderNode, bool) {
+	node, ok := t.byID[id]
+	return node, ok
+}
+
+// Find returns the folder on the given path, like "Contracts/2024",
+// names are compared case insensitive
+func (t *FolderTree) Find(path string) (*FolderNode, error) {
+	node := t.root
+	for _, name := range splitFolderPath(path) {
+		if node = node.child(name); node == nil {
+			return nil, ErrFolderNotFound
+		}
+	}
+	if node == t.root {
+		return nil, ErrFolderNotFound
+	}
+	return node, nil
+}
+
+// Ensure returns the folder on the given path, creating the missing
+// folders on the way as active ones
+func (t *FolderTree) Ensure(path string) (*FolderNode, error) {
+	names := splitFolderPath(path)
+	if len(names) == 0 {
+		return nil, ErrFolderNotFound
+	}
+
+	node := t.root
+	for _, name := range names {
+		if child := node.child(name); child != nil {
+			node = child
+			continue
+		}
+		if t.client.Client == nil {
+			return nil, errNoFolderTreeClient
+		}
+		folder, err := t.client.CreateFolder(CreateFolderRequest{
+			Name:     name,
+			Active:   true,
+			ParentID: node.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		child := &FolderNode{Folder: folder}
+		t.byID[folder.ID] = child
+		t.attach(node, child)
+		node = child
+	}
+	return node, nil
+}
+
+// Documents gets the documents inside the folder on the given path,
+// you can filter this list by employee_id
+func (t *FolderTree) Documents(path string, filter url.Values) ([]Document, error) {
+	node, err := t.Find(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.client.Client == nil {
+		return nil, errNoFolderTreeClient
+	}
+
+	q := url.Values{}
+	for k, v := range filter {
+		q[k] = v
+	}
+	q.Set("folder_id", strconv.Itoa(node.ID))
+	return t.client.ListDocuments(q)
+}
+
+// attach adds the node as a child of parent keeping them sorted by name
+func (t *FolderTree) attach(parent, node *FolderNode) {
+	if parent != t.root {
+		node.Parent = parent
+	}
+	parent.Children = append(parent.Children, node)
+	sort.SliceStable(parent.Children, func(i, j int) bool {
+		return strings.ToLower(parent.Children[i].Name) < strings.ToLower(parent.Children[j].Name)
+	})
+}
+
+// splitFolderPath returns the non empty names of the given path
+func splitFolderPath(path string) []string {
+	var names []string
+	for _, name := range strings.Split(path, "/") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
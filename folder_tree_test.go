@@ -0,0 +1,54 @@
+package factorial
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewFolderTreeCycles(t *testing.T) {
+	tree := NewFolderTree([]Folder{
+		{ID: 1, Name: "A", ParentID: 2},
+		{ID: 2, Name: "B", ParentID: 1},
+		{ID: 3, Name: "C", ParentID: 1},
+		{ID: 4, Name: "D", ParentID: 4},
+		{ID: 5, Name: "E", ParentID: 99},
+	})
+
+	tests := []struct {
+		id   int
+		path string
+	}{
+		{1, "A"},
+		{2, "B"},
+		{3, "A/C"},
+		{4, "D"},
+		{5, "E"},
+	}
+	for _, tt := range tests {
+		node, ok := tree.Get(tt.id)
+		if !ok {
+			t.Fatalf("folder %d not found", tt.id)
+		}
+		if got := node.Path(); got != tt.path {
+			t.Errorf("Path() of %d = %q, want %q", tt.id, got, tt.path)
+		}
+	}
+}
+
+func TestFolderTreeEnsureFailedCreate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":"invalid"}`))
+	}))
+	defer srv.Close()
+
+	tree := NewFolderTree(nil)
+	tree.client = Client{Client: srv.Client(), apiURL: srv.URL}
+	if _, err := tree.Ensure("Contracts/2024"); !IsUnprocessable(err) {
+		t.Fatalf("err = %v, want unprocessable", err)
+	}
+	if len(tree.Roots()) != 0 {
+		t.Errorf("roots = %d, want none stored", len(tree.Roots()))
+	}
+}
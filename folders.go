@@ -9,6 +9,10 @@ const (
 	folderURL = "/api/v1/folders"
 )
 
+// FolderTypeCustom is the type of the folders created by the company,
+// the other types are managed by Factorial
+const FolderTypeCustom = "custom"
+
 // Folder contains all the folder information
 type Folder struct {
 	ID        int    `json:"id"`
@@ -16,6 +20,7 @@ type Folder struct {
 	Name      string `json:"name"`
 	Type      string `json:"type"`
 	Active    bool   `json:"active"`
+	ParentID  int    `json:"parent_id"` // Zero for root folders
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -23,15 +28,17 @@ type Folder struct {
 // CreateFolderRequest keeps the information needed
 // for create a new folder
 type CreateFolderRequest struct {
-	Name   string `json:"name"`
-	Active bool   `json:"active"`
+	Name     string `json:"name"`
+	Active   bool   `json:"active"`
+	ParentID int    `json:"parent_id,omitempty"`
 }
 
 // UpdateFolderRequest keeps the information needed
 // for update a folder
 type UpdateFolderRequest struct {
-	Name   string `json:"name"`
-	Active bool   `json:"active"`
+	Name     string `json:"name"`
+	Active   bool   `json:"active"`
+	ParentID int    `json:"parent_id,omitempty"`
 }
 
 // CreateFolder creates a new folder in your company
@@ -47,6 +54,10 @@ func (c Client) CreateFolder(f CreateFolderRequest) (Folder, error) {
 	if err != nil {
 		return folder, err
 	}
+	if err := checkResponse(resp); err != nil {
+		return folder, err
+	}
+	defer resp.Body.Close()
 
 	if err := json.NewDecoder(resp.Body).Decode(&folder); err != nil {
 		return folder, err
@@ -55,6 +66,20 @@ func (c Client) CreateFolder(f CreateFolderRequest) (Folder, error) {
 	return folder, nil
 }
 
+// DeleteFolder will delete the given folderID
+func (c Client) DeleteFolder(id string) error {
+	resp, err := c.delete(folderURL + "/" + id)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
 // GetFolder gets all information for the given folderID
 func (c Client) GetFolder(id string) (Folder, error) {
 	var folder Folder
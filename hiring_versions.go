@@ -2,13 +2,42 @@ package factorial
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 const (
 	hiringVersionURL = "/api/v1/hiring_versions"
 )
 
+// Compensation types of a hiring version
+const (
+	CompensationHourly  = "hourly"
+	CompensationMonthly = "monthly"
+	CompensationYearly  = "yearly"
+)
+
+// Working period units of a hiring version
+const (
+	WorkingPeriodDay   = "day"
+	WorkingPeriodWeek  = "week"
+	WorkingPeriodMonth = "month"
+	WorkingPeriodYear  = "year"
+)
+
+// ErrEffectiveBeforeStart is returned when a hiring version would be
+// effective before the starting date of the employee
+var ErrEffectiveBeforeStart = errors.New("factorial: hiring version effective before the employee start date")
+
+// ErrHiringVersionNotFound is returned when the hiring version to update doesn't exist
+var ErrHiringVersionNotFound = errors.New("factorial: hiring version not found")
+
+// ErrEmployeeNotFound is returned when the employee of a hiring version doesn't exist
+var ErrEmployeeNotFound = errors.New("factorial: employee not found")
+
 // HiringVersion keeps the basic information related
 // with hiring versions in Factorial
 type HiringVersion struct {
@@ -41,3 +70,146 @@ func (c Client) ListHiringVersions(filter url.Values) ([]HiringVersion, error) {
 
 	return hiringVersions, nil
 }
+
+// CreateHiringVersionRequest keeps the information needed
+// for create a new hiring version, e.g. when promoting an employee
+type CreateHiringVersionRequest struct {
+	EmployeeID                    int    `json:"employee_id"`
+	EffectiveOn                   string `json:"effective_on"`                      // Date from which this contract is valid
+	BaseCompensationAmountInCents int    `json:"base_compensation_amount_in_cents"` // Gross salary in cents
+	BaseCompensationType          string `json:"base_compensation_type"`            // Possible values: hourly, monthly, yearly
+	JobTitle                      string `json:"job_title,omitempty"`
+	WorkingHoursInCents           int    `json:"working_hours_in_cents,omitempty"` // 4000 means 40 hours
+	WorkingPeriodUnit             string `json:"working_period_unit,omitempty"`    // Possible values: day, week, month, year
+}
+
+// UpdateHiringVersionRequest keeps the information
+// that can be updated on a hiring version
+type UpdateHiringVersionRequest struct {
+	EffectiveOn                   string `json:"effective_on,omitempty"`
+	BaseCompensationAmountInCents int    `json:"base_compensation_amount_in_cents,omitempty"`
+	BaseCompensationType          string `json:"base_compensation_type,omitempty"`
+	JobTitle                      string `json:"job_title,omitempty"`
+	WorkingHoursInCents           int    `json:"working_hours_in_cents,omitempty"`
+	WorkingPeriodUnit             string `json:"working_period_unit,omitempty"`
+}
+
+// CreateHiringVersion creates a new hiring version for the employee.
+// ErrEffectiveBeforeStart is returned if the effective date
+// precedes the StartDate of the employee and ErrEmployeeNotFound
+// if the employee doesn't exist.
+// Restricted to admin users.
+func (c Client) CreateHiringVersion(hv CreateHiringVersionRequest) (HiringVersion, error) {
+	var hiringVersion HiringVersion
+
+	if err := c.checkEffectiveOn(hv.EmployeeID, hv.EffectiveOn); err != nil {
+		return hiringVersion, err
+	}
+
+	bytes, err := json.Marshal(hv)
+	if err != nil {
+		return hiringVersion, err
+	}
+
+	resp, err := c.post(hiringVersionURL, bytes)
+	if err != nil {
+		return hiringVersion, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return hiringVersion, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&hiringVersion); err != nil {
+		return hiringVersion, err
+	}
+
+	return hiringVersion, nil
+}
+
+// UpdateHiringVersion updates the given hiring version id with the given
+// request data. When the effective date changes ErrEffectiveBeforeStart is
+// returned if it precedes the StartDate of the employee.
+// Restricted to admin users.
+func (c Client) UpdateHiringVersion(id string, hv UpdateHiringVersionRequest) (HiringVersion, error) {
+	var hiringVersion HiringVersion
+
+	if hv.EffectiveOn != "" {
+		employeeID, err := c.hiringVersionEmployee(id)
+		if err != nil {
+			return hiringVersion, err
+		}
+		if err := c.checkEffectiveOn(employeeID, hv.EffectiveOn); err != nil {
+			return hiringVersion, err
+		}
+	}
+
+	bytes, err := json.Marshal(hv)
+	if err != nil {
+		return hiringVersion, err
+	}
+
+	resp, err := c.put(hiringVersionURL+"/"+id, bytes)
+	if err != nil {
+		return hiringVersion, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return hiringVersion, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&hiringVersion); err != nil {
+		return hiringVersion, err
+	}
+
+	return hiringVersion, nil
+}
+
+// checkEffectiveOn returns ErrEffectiveBeforeStart if the effective
+// date precedes the StartDate of the employee, employees without
+// StartDate are not checked and missing ones are an error
+func (c Client) checkEffectiveOn(employeeID int, effectiveOn string) error {
+	effective, err := time.Parse(DateLayout, effectiveOn)
+	if err != nil {
+		return err
+	}
+
+	employee, err := c.GetEmployee(strconv.Itoa(employeeID))
+	if err != nil {
+		var apiErr APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return ErrEmployeeNotFound
+		}
+		return err
+	}
+	if employee.ID == 0 {
+		return ErrEmployeeNotFound
+	}
+	if employee.StartDate == "" {
+		return nil
+	}
+	start, err := time.Parse(DateLayout, employee.StartDate)
+	if err != nil {
+		return err
+	}
+	if effective.Before(start) {
+		return ErrEffectiveBeforeStart
+	}
+
+	return nil
+}
+
+// hiringVersionEmployee returns the employee of the given hiring version id
+func (c Client) hiringVersionEmployee(id string) (int, error) {
+	hiringVersions, err := c.ListHiringVersions(nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, hv := range hiringVersions {
+		if strconv.Itoa(hv.ID) == id {
+			return hv.EmployeeID, nil
+		}
+	}
+
+	return 0, ErrHiringVersionNotFound
+}
@@ -0,0 +1,44 @@
+package factorial
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateHiringVersionChecksStartDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"missing employee", http.StatusNotFound, `{"error":"not found"}`, ErrEmployeeNotFound},
+		{"empty employee", http.StatusOK, `{}`, ErrEmployeeNotFound},
+		{"no start date", http.StatusOK, `{"id":1}`, nil},
+		{"before start date", http.StatusOK, `{"id":1,"start_date":"2024-03-01"}`, ErrEffectiveBeforeStart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					created = true
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := Client{Client: srv.Client(), apiURL: srv.URL}
+			_, err := c.CreateHiringVersion(CreateHiringVersionRequest{EmployeeID: 1, EffectiveOn: "2024-02-01"})
+			if err != tt.want {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if created != (tt.want == nil) {
+				t.Errorf("created = %v, want %v", created, tt.want == nil)
+			}
+		})
+	}
+}
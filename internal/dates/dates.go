@@ -0,0 +1,9 @@
+// Package dates has the day arithmetic shared by the reports.
+package dates
+
+import "time"
+
+// Truncate returns the midnight of the given time on its location
+func Truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
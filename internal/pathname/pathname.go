@@ -0,0 +1,24 @@
+// Package pathname builds file names from values coming from the API.
+package pathname
+
+import "strings"
+
+// Sanitize makes the name safe to be used as a single path element,
+// separators and reserved characters are replaced and "." or ".." can't
+// escape the parent directory
+func Sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 32 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,62 @@
+// Package progress persists the progress of resumable runs on
+// append only files, with a line per item already processed.
+package progress
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// File is an append only progress file, safe for concurrent use
+type File struct {
+	mu   sync.Mutex
+	path string
+}
+
+// Open reads the given file calling parse with each non empty line,
+// a missing file is an empty progress
+func Open(path string, parse func(line string) error) (*File, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &File{path: path}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		if err := parse(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &File{path: path}, nil
+}
+
+// Append writes a line with the given values separated by spaces
+func (f *File) Append(values ...interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(file, values...); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
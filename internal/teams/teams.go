@@ -0,0 +1,35 @@
+// Package teams resolves the teams of the employees.
+package teams
+
+import "github.com/arexio/factorial-go"
+
+// ByEmployee returns the names of the teams of each employee, joining the
+// members listed on the teams with the TeamIDs of the employees
+func ByEmployee(teams []factorial.Team, employees []factorial.Employee) map[int][]string {
+	names := make(map[int][]string)
+	add := func(employeeID int, name string) {
+		for _, n := range names[employeeID] {
+			if n == name {
+				return
+			}
+		}
+		names[employeeID] = append(names[employeeID], name)
+	}
+
+	byID := make(map[int]string)
+	for _, t := range teams {
+		byID[t.ID] = t.Name
+		for _, id := range t.EmployeeIDs {
+			add(id, t.Name)
+		}
+	}
+	for _, e := range employees {
+		for _, id := range e.TeamIDs {
+			if name, ok := byID[id]; ok {
+				add(e.ID, name)
+			}
+		}
+	}
+
+	return names
+}
@@ -1,12 +1,49 @@
 package factorial
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
 
 const (
 	leaveTypeURL = "/api/v1/leave_types"
 	leaveURL     = "/api/v1/leaves"
 )
 
+// MaxLeaveAttachmentSize is the maximum size in bytes
+// allowed for a leave attachment
+const MaxLeaveAttachmentSize = 10 << 20
+
+// ErrAttachmentTooLarge is returned when the attachment
+// exceeds the MaxLeaveAttachmentSize
+var ErrAttachmentTooLarge = errors.New("factorial: attachment exceeds the maximum allowed size")
+
+// CustomLeaveTypeIdentifier is the identifier of the leave types created by the company,
+// the only ones that can be modified via the API
+const CustomLeaveTypeIdentifier = "custom"
+
+// ErrBuiltInLeaveType is returned when trying to modify or delete
+// a leave type that is not a custom one
+var ErrBuiltInLeaveType = errors.New("factorial: only custom leave types can be modified")
+
+// ErrLeaveTypeNotFound is returned when no leave type matches the search
+var ErrLeaveTypeNotFound = errors.New("factorial: leave type not found")
+
+// Possible values for the status of a leave
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
 // LeaveType contains all the leave type information
 type LeaveType struct {
 	ID               int    `json:"id"`
@@ -35,16 +72,17 @@ type CreateLeaveTypeRequest struct {
 }
 
 // UpdateLeaveTypeRequest keeps the information needed
-// for update a leave type
+// for update a leave type.
+// Flags are pointers so they can be disabled, use Bool for set them.
 type UpdateLeaveTypeRequest struct {
-	Accrues          bool   `json:"accrues,omitempty"`
-	Active           bool   `json:"active,omitempty"`
-	ApprovalRequired bool   `json:"approval_required,omitempty"`
-	Attachment       bool   `json:"attachment,omitempty"`
+	Accrues          *bool  `json:"accrues,omitempty"`
+	Active           *bool  `json:"active,omitempty"`
+	ApprovalRequired *bool  `json:"approval_required,omitempty"`
+	Attachment       *bool  `json:"attachment,omitempty"`
 	Color            string `json:"color,omitempty"`
 	Name             string `json:"name,omitempty"`
-	Visibility       bool   `json:"visibility,omitempty"`
-	Workable         bool   `json:"workable,omitempty"`
+	Visibility       *bool  `json:"visibility,omitempty"`
+	Workable         *bool  `json:"workable,omitempty"`
 }
 
 // CreateLeaveType creates a new leave type.
@@ -86,11 +124,61 @@ func (c Client) ListLeaveTypes() ([]LeaveType, error) {
 	return leaveTypes, nil
 }
 
+// IsCustom returns whether the leave type was created by the company
+// and therefore can be modified via the API
+func (lt LeaveType) IsCustom() bool {
+	return lt.Identifier == CustomLeaveTypeIdentifier
+}
+
+// GetLeaveType gets all information for the given leave type id,
+// an APIError is returned if it doesn't exist
+func (c Client) GetLeaveType(id string) (LeaveType, error) {
+	var leaveType LeaveType
+
+	resp, err := c.get(leaveTypeURL+"/"+id, nil)
+	if err != nil {
+		return leaveType, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return leaveType, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&leaveType); err != nil {
+		return leaveType, err
+	}
+
+	return leaveType, nil
+}
+
+// FindLeaveTypeByName gets the leave type with the given name,
+// the comparison is case insensitive.
+// If none matches ErrLeaveTypeNotFound is returned.
+func (c Client) FindLeaveTypeByName(name string) (LeaveType, error) {
+	leaveTypes, err := c.ListLeaveTypes()
+	if err != nil {
+		return LeaveType{}, err
+	}
+
+	for _, lt := range leaveTypes {
+		if strings.EqualFold(lt.Name, name) {
+			return lt, nil
+		}
+	}
+
+	return LeaveType{}, ErrLeaveTypeNotFound
+}
+
 // UpdateLeaveType update the given leave type id with the given
-// request data
+// request data.
+// Built-in leave types can't be modified, ErrBuiltInLeaveType is returned for them.
 func (c Client) UpdateLeaveType(id string, lt UpdateLeaveTypeRequest) (LeaveType, error) {
 	var leaveType LeaveType
 
+	if err := c.checkCustomLeaveType(id); err != nil {
+		return leaveType, err
+	}
+
 	bytes, err := json.Marshal(lt)
 	if err != nil {
 		return leaveType, err
@@ -108,6 +196,40 @@ func (c Client) UpdateLeaveType(id string, lt UpdateLeaveTypeRequest) (LeaveType
 	return leaveType, nil
 }
 
+// DeleteLeaveType deletes the given leave type id.
+// Built-in leave types can't be deleted, ErrBuiltInLeaveType is returned for them.
+// Restricted to admin users.
+func (c Client) DeleteLeaveType(id string) error {
+	if err := c.checkCustomLeaveType(id); err != nil {
+		return err
+	}
+
+	resp, err := c.delete(leaveTypeURL + "/" + id)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// checkCustomLeaveType returns ErrBuiltInLeaveType if the
+// given leave type id is not a custom one
+func (c Client) checkCustomLeaveType(id string) error {
+	leaveType, err := c.GetLeaveType(id)
+	if err != nil {
+		return err
+	}
+	if !leaveType.IsCustom() {
+		return ErrBuiltInLeaveType
+	}
+
+	return nil
+}
+
 // Leave contains all the leave information
 type Leave struct {
 	ID          int    `json:"id"`
@@ -117,6 +239,9 @@ type Leave struct {
 	HalfDay     string `json:"half_day"`
 	LeaveTypeID int    `json:"leave_type_id"`
 	StartOn     string `json:"start_on"`
+	Status      string `json:"status"`      // Possible values: pending, approved, rejected, cancelled
+	ApproverID  int    `json:"approver_id"` // Employee that approved or rejected the leave
+	ApprovedAt  string `json:"approved_at"` // When the leave was approved or rejected
 }
 
 // CreateLeaveRequest keeps the information needed
@@ -217,3 +342,189 @@ func (c Client) UpdateLeave(id string, lt UpdateLeaveRequest) (Leave, error) {
 
 	return leave, nil
 }
+
+// ApproveLeave approves the given pending leave id.
+// Restricted to admins and the timeoff manager of the leave's employee.
+func (c Client) ApproveLeave(id string) (Leave, error) {
+	var leave Leave
+
+	resp, err := c.post(leaveURL+"/"+id+"/approve", nil)
+	if err != nil {
+		return leave, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return leave, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&leave); err != nil {
+		return leave, err
+	}
+
+	return leave, nil
+}
+
+// RejectLeave rejects the given pending leave id with the given reason.
+// Restricted to admins and the timeoff manager of the leave's employee.
+func (c Client) RejectLeave(id, reason string) (Leave, error) {
+	var leave Leave
+
+	bytes, err := json.Marshal(map[string]string{
+		"reason": reason,
+	})
+	if err != nil {
+		return leave, err
+	}
+
+	resp, err := c.post(leaveURL+"/"+id+"/reject", bytes)
+	if err != nil {
+		return leave, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return leave, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&leave); err != nil {
+		return leave, err
+	}
+
+	return leave, nil
+}
+
+// ListPendingLeaves gets all the pending leaves that the given manager
+// has to approve, the approver is resolved using the TimeoffManagerID
+// of the employee that requested the leave.
+func (c Client) ListPendingLeaves(managerID int) ([]Leave, error) {
+	var pending []Leave
+
+	employees, err := c.ListEmployees()
+	if err != nil {
+		return pending, err
+	}
+	managed := make(map[int]bool)
+	for _, e := range employees {
+		if e.TimeoffManagerID == managerID {
+			managed[e.ID] = true
+		}
+	}
+
+	leaves, err := c.ListLeaves()
+	if err != nil {
+		return pending, err
+	}
+	for _, l := range leaves {
+		if l.Status == LeaveStatusPending && managed[l.EmployeeID] {
+			pending = append(pending, l)
+		}
+	}
+
+	return pending, nil
+}
+
+// ApproveLeaves approves all the given leaves, it stops on the first
+// error and returns the leaves approved until that moment.
+func (c Client) ApproveLeaves(leaves []Leave) ([]Leave, error) {
+	var approved []Leave
+
+	for _, l := range leaves {
+		leave, err := c.ApproveLeave(strconv.Itoa(l.ID))
+		if err != nil {
+			return approved, err
+		}
+		approved = append(approved, leave)
+	}
+
+	return approved, nil
+}
+
+// LeaveAttachment contains the information of a file
+// attached to a leave, like a sick note
+type LeaveAttachment struct {
+	ID          int    `json:"id"`
+	LeaveID     int    `json:"leave_id"`
+	FileName    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// LeaveAttachmentRequest keeps the information needed
+// for attach a file to a leave
+type LeaveAttachmentRequest struct {
+	File        io.Reader
+	FileName    string
+	ContentType string
+}
+
+// UploadLeaveAttachment attaches the given file to the leave id.
+// Only leaves whose leave type has Attachment enabled accept attachments.
+// Files bigger than MaxLeaveAttachmentSize are rejected with ErrAttachmentTooLarge.
+func (c Client) UploadLeaveAttachment(id string, a LeaveAttachmentRequest) (LeaveAttachment, error) {
+	var attachment LeaveAttachment
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+escapeQuotes(a.FileName)+`"`)
+	h.Set("Content-Type", a.ContentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return attachment, err
+	}
+	n, err := io.Copy(part, io.LimitReader(a.File, MaxLeaveAttachmentSize+1))
+	if err != nil {
+		return attachment, err
+	}
+	if n > MaxLeaveAttachmentSize {
+		return attachment, ErrAttachmentTooLarge
+	}
+	if err := w.Close(); err != nil {
+		return attachment, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.apiURL+leaveURL+"/"+id+"/attachments", body)
+	if err != nil {
+		return attachment, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return attachment, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return attachment, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&attachment); err != nil {
+		return attachment, err
+	}
+
+	return attachment, nil
+}
+
+// DownloadLeaveAttachment gets the content of the given attachment id
+// from the given leave id. The caller must close the returned reader.
+func (c Client) DownloadLeaveAttachment(id, attachmentID string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, c.apiURL+leaveURL+"/"+id+"/attachments/"+attachmentID+"/download", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
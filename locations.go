@@ -18,6 +18,7 @@ type Location struct {
 	AddressLine2       string `json:"address_line_2"`
 	PostalCode         string `json:"postal_code"`
 	CompanyHolidaysIDs []int  `json:"company_holidays_ids"`
+	Timezone           string `json:"timezone"` // IANA time zone. E.g. 'Europe/Madrid'
 }
 
 // GetLocation will get the location linked to the given id
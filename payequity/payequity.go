@@ -0,0 +1,133 @@
+// Package payequity analyses the pay gap between genders, as required by the
+// EU pay transparency rules, over the full-time-equivalent annual salaries
+// of the employees, overall and by role, job title, team and location.
+package payequity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/compensation"
+	"github.com/arexio/factorial-go/internal/teams"
+	"github.com/arexio/factorial-go/timesheet"
+)
+
+// Dimensions used for group the employees
+const (
+	DimensionCompany  = "company"
+	DimensionRole     = "role"
+	DimensionJobTitle = "job_title"
+	DimensionTeam     = "team"
+	DimensionLocation = "location"
+)
+
+// Input is the data analysed, as returned by the factorial.Client
+type Input struct {
+	Employees      []factorial.Employee
+	HiringVersions []factorial.HiringVersion
+	Teams          []factorial.Team
+	Locations      []factorial.Location
+}
+
+// Band is the expected range of the full-time-equivalent
+// annual salary, in cents, for a job title
+type Band struct {
+	JobTitle string `json:"job_title"`
+	Min      int64  `json:"min_in_cents"`
+	Max      int64  `json:"max_in_cents"`
+}
+
+// Options changes how the analysis is done
+type Options struct {
+	// Date the contracts and employment are taken from, today if zero
+	Date time.Time
+	// FullTimeWeeklyHours used for normalise part-time salaries, 40 by default
+	FullTimeWeeklyHours float64
+	// ReferenceGender and ComparedGender are the genders of the gap,
+	// "male" and "female" by default. The gap is the percentage of
+	// the reference salary the compared one is below it.
+	ReferenceGender string
+	ComparedGender  string
+	// MinGroupSize is the minimum number of employees of a gender for
+	// showing their figures, 5 by default, so individuals of small
+	// groups can't be identified
+	MinGroupSize int
+}
+
+// Record is an employee with their normalised salary
+type Record struct {
+	EmployeeID int      `json:"employee_id"`
+	Gender     string   `json:"gender"`
+	Role       string   `json:"role"`
+	JobTitle   string   `json:"job_title"`
+	Teams      []string `json:"teams"`
+	Location   string   `json:"location"`
+	// Salary is the full-time-equivalent annual salary in cents
+	Salary int64 `json:"fte_annual_salary_in_cents"`
+}
+
+// Records returns the employees working on the date with a hiring
+// version effective on it, with their salary normalised to a full
+// time annual one, sorted by employee id
+func Records(in Input, opts Options) []Record {
+	opts = opts.withDefaults()
+	day := opts.Date.Format(factorial.DateLayout)
+
+	teamNames := teams.ByEmployee(in.Teams, in.Employees)
+	locations := make(map[int]string)
+	for _, l := range in.Locations {
+		locations[l.ID] = l.Name
+	}
+	timelines := compensation.NewTimelines(in.HiringVersions)
+
+	var records []Record
+	for _, e := range in.Employees {
+		if (e.StartDate != "" && e.StartDate > day) || (e.TerminatedOn != "" && e.TerminatedOn < day) {
+			continue
+		}
+		hv, ok := timelines[e.ID].At(opts.Date)
+		if !ok {
+			continue
+		}
+		salary := float64(compensation.AnnualSalary(hv))
+		if hours := timesheet.WeeklyHours(hv).Hours(); hours > 0 {
+			salary = salary * opts.FullTimeWeeklyHours / hours
+		} else if hv.BaseCompensationType == factorial.CompensationHourly {
+			salary = float64(hv.BaseCompensationAmountInCents) * opts.FullTimeWeeklyHours * 52
+		}
+
+		r := Record{
+			EmployeeID: e.ID,
+			Gender:     e.Gender,
+			Role:       e.Role,
+			JobTitle:   hv.JobTitle,
+			Teams:      teamNames[e.ID],
+			Location:   locations[e.LocationID],
+			Salary:     int64(salary + 0.5),
+		}
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].EmployeeID < records[j].EmployeeID })
+	return records
+}
+
+func (o Options) withDefaults() Options {
+	if o.Date.IsZero() {
+		o.Date = time.Now()
+	}
+	if o.FullTimeWeeklyHours <= 0 {
+		o.FullTimeWeeklyHours = 40
+	}
+	if o.ReferenceGender == "" {
+		o.ReferenceGender = "male"
+	}
+	if o.ComparedGender == "" {
+		o.ComparedGender = "female"
+	}
+	if o.MinGroupSize <= 0 {
+		o.MinGroupSize = 5
+	}
+	return o
+}
@@ -0,0 +1,228 @@
+package payequity
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/arexio/factorial-go"
+)
+
+// Report is the result of the analysis, ready to be encoded as JSON.
+// It only has aggregated figures, see Outliers for the employees out
+// of their salary band.
+type Report struct {
+	Date            string     `json:"date"`
+	ReferenceGender string     `json:"reference_gender"`
+	ComparedGender  string     `json:"compared_gender"`
+	MinGroupSize    int        `json:"min_group_size"`
+	Groups          []Group    `json:"groups"`
+	Quartiles       []Quartile `json:"quartiles"`
+}
+
+// Group is the gap of the employees sharing the value of a dimension
+type Group struct {
+	Dimension string `json:"dimension"`
+	Value     string `json:"value"`
+	Employees int    `json:"employees"`
+	// Genders has the figures of each gender with enough employees
+	Genders map[string]Stats `json:"genders"`
+	// Suppressed is true when the reference or the compared gender
+	// have less employees than MinGroupSize, the gaps are not computed
+	Suppressed bool `json:"suppressed"`
+	// MeanGap and MedianGap are the percentage of the reference salary
+	// the compared gender earns less, negative if it earns more
+	MeanGap   *float64 `json:"mean_gap_percent,omitempty"`
+	MedianGap *float64 `json:"median_gap_percent,omitempty"`
+}
+
+// Stats of the salaries of a gender in a group, in cents
+type Stats struct {
+	Count  int   `json:"count"`
+	Mean   int64 `json:"mean_in_cents"`
+	Median int64 `json:"median_in_cents"`
+}
+
+// Quartile is the gender distribution of a quarter of the employees
+// sorted by salary, 1 is the lowest paid. The salaries of the quartile
+// are not included, its lowest and highest ones belong to individuals.
+type Quartile struct {
+	Quartile  int            `json:"quartile"`
+	Employees int            `json:"employees"`
+	Genders   map[string]int `json:"genders"` // Percentage of each gender
+}
+
+// Outlier is an employee whose salary is out of the band of their job title.
+// Outliers identify employees, they are meant for HR only.
+type Outlier struct {
+	Record
+	Band Band `json:"band"`
+}
+
+// Analyse builds the report of the employees working on the date of the options
+func Analyse(in Input, opts Options) Report {
+	opts = opts.withDefaults()
+	return NewReport(Records(in, opts), opts)
+}
+
+// NewReport builds the report from the given records
+func NewReport(records []Record, opts Options) Report {
+	opts = opts.withDefaults()
+	r := Report{
+		Date:            opts.Date.Format(factorial.DateLayout),
+		ReferenceGender: opts.ReferenceGender,
+		ComparedGender:  opts.ComparedGender,
+		MinGroupSize:    opts.MinGroupSize,
+		Groups:          []Group{},
+		Quartiles:       []Quartile{},
+	}
+
+	r.Groups = append(r.Groups, group(DimensionCompany, "Company", records, opts))
+	for _, dimension := range []string{DimensionRole, DimensionJobTitle, DimensionTeam, DimensionLocation} {
+		byValue := make(map[string][]Record)
+		for _, rec := range records {
+			for _, v := range values(rec, dimension) {
+				byValue[v] = append(byValue[v], rec)
+			}
+		}
+		keys := make([]string, 0, len(byValue))
+		for v := range byValue {
+			keys = append(keys, v)
+		}
+		sort.Strings(keys)
+		for _, v := range keys {
+			r.Groups = append(r.Groups, group(dimension, v, byValue[v], opts))
+		}
+	}
+
+	r.Quartiles = quartiles(records, opts.MinGroupSize)
+	return r
+}
+
+// Outliers returns the employees whose salary is out of the band of their
+// job title. They identify employees, so they are kept out of the Report
+// and are meant for HR only.
+func Outliers(records []Record, bands []Band) []Outlier {
+	byJobTitle := make(map[string]Band)
+	for _, b := range bands {
+		byJobTitle[b.JobTitle] = b
+	}
+
+	outliers := []Outlier{}
+	for _, rec := range records {
+		b, ok := byJobTitle[rec.JobTitle]
+		if ok && (rec.Salary < b.Min || (b.Max > 0 && rec.Salary > b.Max)) {
+			outliers = append(outliers, Outlier{Record: rec, Band: b})
+		}
+	}
+	return outliers
+}
+
+// WriteJSON writes the report as indented JSON
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// values returns the values of the dimension for the record,
+// employees in several teams belong to each of them
+func values(rec Record, dimension string) []string {
+	var v []string
+	switch dimension {
+	case DimensionRole:
+		v = []string{rec.Role}
+	case DimensionJobTitle:
+		v = []string{rec.JobTitle}
+	case DimensionTeam:
+		v = rec.Teams
+	case DimensionLocation:
+		v = []string{rec.Location}
+	}
+	if len(v) == 0 || (len(v) == 1 && v[0] == "") {
+		return []string{"Unknown"}
+	}
+	return v
+}
+
+func group(dimension, value string, records []Record, opts Options) Group {
+	g := Group{Dimension: dimension, Value: value, Employees: len(records), Genders: make(map[string]Stats)}
+
+	byGender := make(map[string][]int64)
+	for _, rec := range records {
+		byGender[rec.Gender] = append(byGender[rec.Gender], rec.Salary)
+	}
+	for gender, salaries := range byGender {
+		if len(salaries) < opts.MinGroupSize {
+			continue
+		}
+		g.Genders[gender] = stats(salaries)
+	}
+
+	ref, okRef := g.Genders[opts.ReferenceGender]
+	cmp, okCmp := g.Genders[opts.ComparedGender]
+	if !okRef || !okCmp {
+		g.Suppressed = true
+		return g
+	}
+	g.MeanGap = gap(ref.Mean, cmp.Mean)
+	g.MedianGap = gap(ref.Median, cmp.Median)
+	return g
+}
+
+func stats(salaries []int64) Stats {
+	sorted := append([]int64(nil), salaries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum int64
+	for _, s := range sorted {
+		sum += s
+	}
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return Stats{
+		Count:  len(sorted),
+		Mean:   int64(math.Round(float64(sum) / float64(len(sorted)))),
+		Median: median,
+	}
+}
+
+func gap(reference, compared int64) *float64 {
+	if reference == 0 {
+		return nil
+	}
+	g := math.Round(float64(reference-compared)*10000/float64(reference)) / 100
+	return &g
+}
+
+// quartiles splits the employees sorted by salary in four groups, quartiles
+// with less employees than minGroupSize are not reported
+func quartiles(records []Record, minGroupSize int) []Quartile {
+	sorted := append([]Record(nil), records...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Salary < sorted[j].Salary })
+
+	result := []Quartile{}
+	for q := 0; q < 4; q++ {
+		part := sorted[len(sorted)*q/4 : len(sorted)*(q+1)/4]
+		if len(part) == 0 || len(part) < minGroupSize {
+			continue
+		}
+		quartile := Quartile{
+			Quartile:  q + 1,
+			Employees: len(part),
+			Genders:   make(map[string]int),
+		}
+		counts := make(map[string]int)
+		for _, rec := range part {
+			counts[rec.Gender]++
+		}
+		for gender, n := range counts {
+			quartile.Genders[gender] = int(math.Round(float64(n) * 100 / float64(len(part))))
+		}
+		result = append(result, quartile)
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package payroll
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+// Anomaly is a month-over-month change of the salary of an employee
+// bigger than the threshold
+type Anomaly struct {
+	EmployeeID int     `json:"employee_id"`
+	Period     string  `json:"period"`   // Month with the change, "2006-01"
+	Previous   string  `json:"previous"` // Month compared with
+	Field      string  `json:"field"`    // gross or net
+	From       int64   `json:"from_in_cents"`
+	To         int64   `json:"to_in_cents"`
+	Change     float64 `json:"change"` // Relative change, 0.25 is +25%
+}
+
+// Anomalies compares the monthly gross and net salary of each employee with
+// the previous month with payslips, reporting the relative changes bigger
+// than threshold (0.2 reports changes over 20%). Months with several payslips
+// are summed up. Employees without salary the previous month are ignored.
+func (d Data) Anomalies(threshold float64) ([]Anomaly, error) {
+	type month struct {
+		period     string
+		gross, net int64
+	}
+	byEmployee := make(map[int]map[string]*month)
+	for _, p := range d.Payslips {
+		start, err := time.Parse(factorial.DateLayout, p.StartDate)
+		if err != nil {
+			continue
+		}
+		a, err := ParseAmounts(p)
+		if err != nil {
+			return nil, err
+		}
+		if byEmployee[p.EmployeeID] == nil {
+			byEmployee[p.EmployeeID] = make(map[string]*month)
+		}
+		period := start.Format("2006-01")
+		m, ok := byEmployee[p.EmployeeID][period]
+		if !ok {
+			m = &month{period: period}
+			byEmployee[p.EmployeeID][period] = m
+		}
+		m.gross += a.Gross
+		m.net += a.Net
+	}
+
+	var anomalies []Anomaly
+	for employeeID, months := range byEmployee {
+		sorted := make([]*month, 0, len(months))
+		for _, m := range months {
+			sorted = append(sorted, m)
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].period < sorted[j].period })
+
+		for i := 1; i < len(sorted); i++ {
+			prev, cur := sorted[i-1], sorted[i]
+			for _, f := range []struct {
+				name     string
+				from, to int64
+			}{
+				{"gross", prev.gross, cur.gross},
+				{"net", prev.net, cur.net},
+			} {
+				if f.from == 0 {
+					continue
+				}
+				change := float64(f.to-f.from) / float64(f.from)
+				if math.Abs(change) > threshold {
+					anomalies = append(anomalies, Anomaly{
+						EmployeeID: employeeID,
+						Period:     cur.period,
+						Previous:   prev.period,
+						Field:      f.name,
+						From:       f.from,
+						To:         f.to,
+						Change:     change,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].EmployeeID != anomalies[j].EmployeeID {
+			return anomalies[i].EmployeeID < anomalies[j].EmployeeID
+		}
+		if anomalies[i].Period != anomalies[j].Period {
+			return anomalies[i].Period < anomalies[j].Period
+		}
+		return anomalies[i].Field < anomalies[j].Field
+	})
+	return anomalies, nil
+}
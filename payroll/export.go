@@ -0,0 +1,132 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+// WriteCSV writes the summaries as CSV, amounts in cents
+func WriteCSV(w io.Writer, summaries []Summary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"period", "group", "payslips", "employees", "gross_in_cents", "net_in_cents",
+		"irpf_base_in_cents", "irpf_withholding_in_cents", "social_security_base_in_cents",
+	}); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		if err := cw.Write([]string{
+			s.Period,
+			s.Group,
+			strconv.Itoa(s.Payslips),
+			strconv.Itoa(s.Employees),
+			strconv.FormatInt(s.Gross, 10),
+			strconv.FormatInt(s.Net, 10),
+			strconv.FormatInt(s.IRPFBase, 10),
+			strconv.FormatInt(s.IRPFWithholding, 10),
+			strconv.FormatInt(s.SocialSecurityBase, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the given value, like the summaries or
+// the anomalies, as indented JSON
+func WriteJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WithholdingSummary is the annual IRPF summary of an employee, in the
+// way of the Spanish form 190 (key A, employees' salaries)
+type WithholdingSummary struct {
+	EmployeeID  int    `json:"employee_id"`
+	Identifier  string `json:"identifier"` // NIF of the perceptor
+	FullName    string `json:"full_name"`
+	Key         string `json:"key"`                  // Perception key, "A" for employees
+	Perceptions int64  `json:"perceptions_in_cents"` // Gross salary of the year
+	Withholding int64  `json:"withholding_in_cents"` // IRPF withheld on the year
+	Payslips    int    `json:"payslips"`
+}
+
+// Form190 returns the annual withholding summary of every employee with
+// payslips on the given year, sorted by identifier
+func (d Data) Form190(year int) ([]WithholdingSummary, error) {
+	employees := make(map[int]WithholdingSummary)
+	for _, e := range d.Employees {
+		name := e.FullName
+		if name == "" {
+			name = strings.TrimSpace(e.FirstName + " " + e.LastName)
+		}
+		employees[e.ID] = WithholdingSummary{EmployeeID: e.ID, Identifier: e.Identifier, FullName: name, Key: "A"}
+	}
+
+	summaries := make(map[int]*WithholdingSummary)
+	for _, p := range d.Payslips {
+		start, err := time.Parse(factorial.DateLayout, p.StartDate)
+		if err != nil || start.Year() != year {
+			continue
+		}
+		a, err := ParseAmounts(p)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := summaries[p.EmployeeID]
+		if !ok {
+			e, found := employees[p.EmployeeID]
+			if !found {
+				e = WithholdingSummary{EmployeeID: p.EmployeeID, Key: "A"}
+			}
+			s = &e
+			summaries[p.EmployeeID] = s
+		}
+		s.Perceptions += a.Gross
+		s.Withholding += a.IRPFWithholding
+		s.Payslips++
+	}
+
+	result := make([]WithholdingSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Identifier != result[j].Identifier {
+			return result[i].Identifier < result[j].Identifier
+		}
+		return result[i].EmployeeID < result[j].EmployeeID
+	})
+	return result, nil
+}
+
+// WriteForm190CSV writes the annual withholding summaries as CSV
+func WriteForm190CSV(w io.Writer, summaries []WithholdingSummary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"nif", "full_name", "key", "perceptions_in_cents", "withholding_in_cents", "payslips"}); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		if err := cw.Write([]string{
+			s.Identifier,
+			s.FullName,
+			s.Key,
+			strconv.FormatInt(s.Perceptions, 10),
+			strconv.FormatInt(s.Withholding, 10),
+			strconv.Itoa(s.Payslips),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
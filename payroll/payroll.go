@@ -0,0 +1,218 @@
+// Package payroll builds payroll summaries from the payslips: totals of gross
+// and net salary, IRPF withholdings and social security bases by month or year,
+// grouped by team or location, plus month-over-month anomalies per employee.
+package payroll
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/teams"
+)
+
+// Ways of grouping the summaries
+const (
+	GroupCompany  = "company"
+	GroupTeam     = "team"
+	GroupLocation = "location"
+)
+
+var _ Client = factorial.Client{}
+
+// Client is the subset of the factorial.Client used for fetch the payroll data
+type Client interface {
+	ListPayslips(filter url.Values) ([]factorial.Payslip, error)
+	ListEmployees() ([]factorial.Employee, error)
+	ListTeams() ([]factorial.Team, error)
+	ListLocations() ([]factorial.Location, error)
+}
+
+// Data holds everything needed for build the summaries
+type Data struct {
+	Payslips  []factorial.Payslip
+	Employees []factorial.Employee
+	Teams     []factorial.Team
+	Locations []factorial.Location
+}
+
+// Fetch gets the payslips matching the filter, see ListPayslips,
+// and the employees, teams and locations of the company
+func Fetch(c Client, filter url.Values) (Data, error) {
+	var data Data
+	var err error
+
+	if data.Payslips, err = c.ListPayslips(filter); err != nil {
+		return data, err
+	}
+	if data.Employees, err = c.ListEmployees(); err != nil {
+		return data, err
+	}
+	if data.Teams, err = c.ListTeams(); err != nil {
+		return data, err
+	}
+	if data.Locations, err = c.ListLocations(); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// Totals are the sums of the amounts of a set of payslips, in cents
+type Totals struct {
+	Payslips           int   `json:"payslips"`
+	Employees          int   `json:"employees"`
+	Gross              int64 `json:"gross_in_cents"`
+	Net                int64 `json:"net_in_cents"`
+	IRPFBase           int64 `json:"irpf_base_in_cents"`
+	IRPFWithholding    int64 `json:"irpf_withholding_in_cents"`
+	SocialSecurityBase int64 `json:"social_security_base_in_cents"`
+}
+
+// Summary is the totals of a period for a group
+type Summary struct {
+	Period string `json:"period"` // "2006-01" for months, "2006" for years
+	Group  string `json:"group"`  // Team or location name, or "Company"
+	Totals
+}
+
+// Amounts are the parsed amounts of a payslip, in cents
+type Amounts struct {
+	Gross              int64
+	Net                int64
+	IRPFBase           int64
+	IRPFPercentage     float64
+	IRPFWithholding    int64 // IRPFBase * IRPFPercentage
+	SocialSecurityBase int64
+}
+
+// ParseAmounts parses the amounts of the payslip,
+// some of them are sent as strings by Factorial
+func ParseAmounts(p factorial.Payslip) (Amounts, error) {
+	a := Amounts{
+		IRPFBase:           int64(p.BaseIRPFInCents),
+		SocialSecurityBase: int64(p.BaseCotizationInCents),
+	}
+	var err error
+	if a.Gross, err = parseCents(p.GrossSalaryInCents); err != nil {
+		return a, fmt.Errorf("payroll: payslip %d gross salary: %w", p.ID, err)
+	}
+	if a.Net, err = parseCents(p.NetSalaryInCents); err != nil {
+		return a, fmt.Errorf("payroll: payslip %d net salary: %w", p.ID, err)
+	}
+	if a.IRPFPercentage, err = parseNumber(p.IRPFPercentage); err != nil {
+		return a, fmt.Errorf("payroll: payslip %d IRPF percentage: %w", p.ID, err)
+	}
+	a.IRPFWithholding = int64(math.Round(float64(a.IRPFBase) * a.IRPFPercentage / 100))
+
+	return a, nil
+}
+
+// Monthly returns the totals of each month for each group, sorted by period and group
+func (d Data) Monthly(groupBy string) ([]Summary, error) {
+	return d.summarize(groupBy, "2006-01")
+}
+
+// Yearly returns the totals of each year for each group, sorted by period and group
+func (d Data) Yearly(groupBy string) ([]Summary, error) {
+	return d.summarize(groupBy, "2006")
+}
+
+func (d Data) summarize(groupBy, layout string) ([]Summary, error) {
+	groups := d.groups(groupBy)
+
+	type key struct{ period, group string }
+	summaries := make(map[key]*Summary)
+	employees := make(map[key]map[int]bool)
+	for _, p := range d.Payslips {
+		start, err := time.Parse(factorial.DateLayout, p.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("payroll: payslip %d start date: %w", p.ID, err)
+		}
+		a, err := ParseAmounts(p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups(p.EmployeeID) {
+			k := key{start.Format(layout), group}
+			s, ok := summaries[k]
+			if !ok {
+				s = &Summary{Period: k.period, Group: group}
+				summaries[k] = s
+				employees[k] = make(map[int]bool)
+			}
+			s.Payslips++
+			employees[k][p.EmployeeID] = true
+			s.Gross += a.Gross
+			s.Net += a.Net
+			s.IRPFBase += a.IRPFBase
+			s.IRPFWithholding += a.IRPFWithholding
+			s.SocialSecurityBase += a.SocialSecurityBase
+		}
+	}
+
+	result := make([]Summary, 0, len(summaries))
+	for k, s := range summaries {
+		s.Employees = len(employees[k])
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Period != result[j].Period {
+			return result[i].Period < result[j].Period
+		}
+		return result[i].Group < result[j].Group
+	})
+	return result, nil
+}
+
+// groups returns a function that gives the names of the groups of an employee,
+// employees can be in more than one team so their payslips count on each
+func (d Data) groups(groupBy string) func(employeeID int) []string {
+	switch groupBy {
+	case GroupTeam:
+		byEmployee := teams.ByEmployee(d.Teams, d.Employees)
+		return func(employeeID int) []string {
+			if len(byEmployee[employeeID]) == 0 {
+				return []string{"No team"}
+			}
+			return byEmployee[employeeID]
+		}
+	case GroupLocation:
+		names := make(map[int]string)
+		for _, l := range d.Locations {
+			names[l.ID] = l.Name
+		}
+		locations := make(map[int]string)
+		for _, e := range d.Employees {
+			locations[e.ID] = names[e.LocationID]
+		}
+		return func(employeeID int) []string {
+			if locations[employeeID] == "" {
+				return []string{"No location"}
+			}
+			return []string{locations[employeeID]}
+		}
+	default:
+		return func(int) []string { return []string{"Company"} }
+	}
+}
+
+func parseCents(s string) (int64, error) {
+	f, err := parseNumber(s)
+	return int64(math.Round(f)), err
+}
+
+// parseNumber parses a number sent as string, empty values are zero
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,229 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/timesheet"
+)
+
+// Reasons of a discrepancy
+const (
+	ReasonAmount     = "amount_mismatch" // The gross salary differs from the expected one
+	ReasonNoContract = "no_contract"     // No hiring version is effective on the payslip period
+	ReasonInvalid    = "invalid_payslip" // The payslip dates or amounts can't be parsed
+)
+
+// ReconcileInput is the data compared by Reconcile
+type ReconcileInput struct {
+	Payslips       []factorial.Payslip
+	HiringVersions []factorial.HiringVersion
+	Employees      []factorial.Employee
+	Leaves         []factorial.Leave
+}
+
+// ReconcileOptions changes how the expected salary is computed
+type ReconcileOptions struct {
+	// UnpaidLeaveTypeIDs are the leave types not paid, their approved
+	// leaves reduce the expected salary of the days they cover
+	UnpaidLeaveTypeIDs []int
+	// PaymentsPerYear splits yearly compensations, 12 by default.
+	// Use 14 when the extra payments are paid on their own payslips.
+	PaymentsPerYear int
+	// ToleranceInCents and TolerancePercent are the allowed difference, the
+	// biggest of both applies. TolerancePercent is a ratio, 0.01 is 1%.
+	ToleranceInCents int64
+	TolerancePercent float64
+}
+
+// Discrepancy is a payslip whose gross salary doesn't match the contract
+type Discrepancy struct {
+	PayslipID       int    `json:"payslip_id"`
+	EmployeeID      int    `json:"employee_id"`
+	StartDate       string `json:"start_date"`
+	EndDate         string `json:"end_date"`
+	HiringVersionID int    `json:"hiring_version_id,omitempty"` // Effective at the end of the period
+	Expected        int64  `json:"expected_in_cents"`
+	Actual          int64  `json:"actual_in_cents"`
+	Difference      int64  `json:"difference_in_cents"` // Actual - Expected
+	Reason          string `json:"reason"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Reconcile compares the gross salary of each payslip with the one expected
+// from the hiring versions effective on its period. The expected salary is
+// computed day by day, so changes of contract in the middle of the period,
+// days before the start or after the end of the contract or the termination
+// of the employee, and unpaid leaves are pro-rated. Monthly and yearly
+// compensations are split between the calendar days of the month of each
+// day, so a payslip covering a whole month expects the full salary, hourly
+// ones are paid by the contracted hours of each day.
+// Only the payslips with discrepancies over the tolerance are returned.
+func Reconcile(in ReconcileInput, opts ReconcileOptions) []Discrepancy {
+	payments := opts.PaymentsPerYear
+	if payments <= 0 {
+		payments = 12
+	}
+	terminated := make(map[int]time.Time)
+	for _, e := range in.Employees {
+		if t, err := time.Parse(factorial.DateLayout, e.TerminatedOn); err == nil {
+			terminated[e.ID] = t
+		}
+	}
+	unpaid := unpaidDays(in.Leaves, opts.UnpaidLeaveTypeIDs)
+
+	var discrepancies []Discrepancy
+	for _, p := range in.Payslips {
+		d := Discrepancy{PayslipID: p.ID, EmployeeID: p.EmployeeID, StartDate: p.StartDate, EndDate: p.EndDate}
+
+		a, err := ParseAmounts(p)
+		if err != nil {
+			d.Reason, d.Error = ReasonInvalid, err.Error()
+			discrepancies = append(discrepancies, d)
+			continue
+		}
+		d.Actual = a.Gross
+		start, err := time.Parse(factorial.DateLayout, p.StartDate)
+		if err != nil {
+			d.Reason, d.Error = ReasonInvalid, err.Error()
+			discrepancies = append(discrepancies, d)
+			continue
+		}
+		end, err := time.Parse(factorial.DateLayout, p.EndDate)
+		if err != nil {
+			d.Reason, d.Error = ReasonInvalid, err.Error()
+			discrepancies = append(discrepancies, d)
+			continue
+		}
+
+		if hv, ok := timesheet.EffectiveHiringVersion(in.HiringVersions, p.EmployeeID, end); ok {
+			d.HiringVersionID = hv.ID
+		} else if _, ok := timesheet.EffectiveHiringVersion(in.HiringVersions, p.EmployeeID, start); !ok {
+			d.Reason = ReasonNoContract
+			discrepancies = append(discrepancies, d)
+			continue
+		}
+
+		var expected float64
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			hv, ok := timesheet.EffectiveHiringVersion(in.HiringVersions, p.EmployeeID, day)
+			if !ok || !employed(hv, terminated[p.EmployeeID], day) {
+				continue
+			}
+			paid := 1 - unpaid[p.EmployeeID][day.Format(factorial.DateLayout)]
+			amount := float64(hv.BaseCompensationAmountInCents)
+			monthDays := float64(daysInMonth(day))
+			switch hv.BaseCompensationType {
+			case factorial.CompensationHourly:
+				expected += amount * timesheet.ContractedHours(hv, day).Hours() * paid
+			case factorial.CompensationYearly:
+				expected += amount / float64(payments) / monthDays * paid
+			default:
+				expected += amount / monthDays * paid
+			}
+		}
+		d.Expected = int64(math.Round(expected))
+		d.Difference = d.Actual - d.Expected
+
+		tolerance := math.Max(float64(opts.ToleranceInCents), opts.TolerancePercent*math.Abs(expected))
+		if math.Abs(float64(d.Difference)) > tolerance {
+			d.Reason = ReasonAmount
+			discrepancies = append(discrepancies, d)
+		}
+	}
+
+	sort.SliceStable(discrepancies, func(i, j int) bool {
+		if discrepancies[i].StartDate != discrepancies[j].StartDate {
+			return discrepancies[i].StartDate < discrepancies[j].StartDate
+		}
+		return discrepancies[i].EmployeeID < discrepancies[j].EmployeeID
+	})
+	return discrepancies
+}
+
+// WriteDiscrepanciesCSV writes the discrepancies as CSV
+func WriteDiscrepanciesCSV(w io.Writer, discrepancies []Discrepancy) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"payslip_id", "employee_id", "start_date", "end_date", "hiring_version_id",
+		"expected_in_cents", "actual_in_cents", "difference_in_cents", "reason", "error",
+	}); err != nil {
+		return err
+	}
+	for _, d := range discrepancies {
+		if err := cw.Write([]string{
+			strconv.Itoa(d.PayslipID),
+			strconv.Itoa(d.EmployeeID),
+			d.StartDate,
+			d.EndDate,
+			strconv.Itoa(d.HiringVersionID),
+			strconv.FormatInt(d.Expected, 10),
+			strconv.FormatInt(d.Actual, 10),
+			strconv.FormatInt(d.Difference, 10),
+			d.Reason,
+			d.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// daysInMonth returns the number of days of the month of the day
+func daysInMonth(day time.Time) int {
+	return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// employed returns whether the day is inside the contract and
+// not after the termination of the employee
+func employed(hv factorial.HiringVersion, terminatedOn, day time.Time) bool {
+	if start, err := time.Parse(factorial.DateLayout, hv.StartDate); err == nil && day.Before(start) {
+		return false
+	}
+	if end, err := time.Parse(factorial.DateLayout, hv.EndDate); err == nil && day.After(end) {
+		return false
+	}
+	return terminatedOn.IsZero() || !day.After(terminatedOn)
+}
+
+// unpaidDays returns, per employee and date, the part of the day not paid
+// by approved leaves of the unpaid types, 1 for full days and 0.5 for half days
+func unpaidDays(leaves []factorial.Leave, unpaidTypes []int) map[int]map[string]float64 {
+	types := make(map[int]bool)
+	for _, id := range unpaidTypes {
+		types[id] = true
+	}
+
+	days := make(map[int]map[string]float64)
+	for _, l := range leaves {
+		if !types[l.LeaveTypeID] || (l.Status != factorial.LeaveStatusApproved && l.Status != "") {
+			continue
+		}
+		start, err := time.Parse(factorial.DateLayout, l.StartOn)
+		if err != nil {
+			continue
+		}
+		finish, err := time.Parse(factorial.DateLayout, l.FinishOn)
+		if err != nil {
+			finish = start
+		}
+		ratio := 1.0
+		if l.HalfDay != "" {
+			ratio = 0.5
+		}
+		if days[l.EmployeeID] == nil {
+			days[l.EmployeeID] = make(map[string]float64)
+		}
+		for d := start; !d.After(finish); d = d.AddDate(0, 0, 1) {
+			day := d.Format(factorial.DateLayout)
+			days[l.EmployeeID][day] = math.Min(1, days[l.EmployeeID][day]+ratio)
+		}
+	}
+	return days
+}
@@ -1,8 +1,19 @@
 package factorial
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go/internal/pathname"
 )
 
 const (
@@ -24,6 +35,7 @@ type Payslip struct {
 	EndDate               string `json:"end_date"`
 	EmployeeID            int    `json:"employee_id"`
 	Status                string `json:"status"`
+	File                  string `json:"file"` // URL of the payslip PDF
 }
 
 // ListPayslips gets all the payslips from your company
@@ -45,3 +57,101 @@ func (c Client) ListPayslips(filter url.Values) ([]Payslip, error) {
 
 	return payslips, nil
 }
+
+// GetPayslip gets all information for the given payslip id,
+// an APIError is returned if the payslip doesn't exist
+func (c Client) GetPayslip(id string) (Payslip, error) {
+	var payslip Payslip
+
+	resp, err := c.get(payslipURL+"/"+id, nil)
+	if err != nil {
+		return payslip, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return payslip, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&payslip); err != nil {
+		return payslip, err
+	}
+
+	return payslip, nil
+}
+
+// DownloadPayslip gets the PDF of the given payslip id through the
+// authenticated client. The caller must close the returned reader.
+func (c Client) DownloadPayslip(id string) (io.ReadCloser, error) {
+	payslip, err := c.GetPayslip(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.downloadRequest(context.Background(), c.payslipFileURL(payslip), 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// DownloadPayslips writes the PDF of every payslip of the given month into
+// dir as "employee_identifier/YYYY-MM.pdf", using the employee id when the
+// employee has no identifier. Identifiers are sanitised so they can't
+// escape dir. Files already present are kept, so an
+// interrupted call can be run again. It returns the paths of the files.
+func (c Client) DownloadPayslips(dir string, year int, month time.Month) ([]string, error) {
+	var paths []string
+
+	filter := url.Values{}
+	filter.Set("year", strconv.Itoa(year))
+	filter.Set("month", strconv.Itoa(int(month)))
+	payslips, err := c.ListPayslips(filter)
+	if err != nil {
+		return paths, err
+	}
+
+	employees, err := c.ListEmployees()
+	if err != nil {
+		return paths, err
+	}
+	identifiers := make(map[int]string)
+	for _, e := range employees {
+		identifiers[e.ID] = strings.TrimSpace(e.Identifier)
+	}
+
+	sort.Slice(payslips, func(i, j int) bool { return payslips[i].ID < payslips[j].ID })
+	period := fmt.Sprintf("%04d-%02d", year, int(month))
+	used := make(map[string]bool)
+	for _, p := range payslips {
+		employee := pathname.Sanitize(identifiers[p.EmployeeID])
+		if employee == "_" {
+			employee = strconv.Itoa(p.EmployeeID)
+		}
+		path := filepath.Join(dir, employee, period+".pdf")
+		if used[path] {
+			// More than one payslip on the month, like extra payments
+			path = filepath.Join(dir, employee, period+"-"+strconv.Itoa(p.ID)+".pdf")
+		}
+		used[path] = true
+
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return paths, err
+		}
+		if _, err := c.downloadTo(context.Background(), c.payslipFileURL(p), path); err != nil {
+			return paths, fmt.Errorf("factorial: downloading payslip %d: %w", p.ID, err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// payslipFileURL returns the url of the PDF of the payslip
+func (c Client) payslipFileURL(p Payslip) string {
+	return c.resolveFileURL(p.File, payslipURL+"/"+strconv.Itoa(p.ID)+"/download")
+}
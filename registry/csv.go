@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCSV writes the registry as CSV, with the company and worker
+// identifiers as header, a row per day and the signature placeholders
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	records := [][]string{
+		{"Empresa", r.Company.Name},
+		{"CIF", r.Company.TaxID},
+		{"CCC", r.Company.ContributionID},
+		{"Centro de trabajo", r.Company.Workplace},
+		{"Trabajador", r.Worker.FullName},
+		{"NIF", r.Worker.Identifier},
+		{"NAF", r.Worker.SocialSecurityNumber},
+		{"Periodo", fmt.Sprintf("%02d/%d", int(r.Month), r.Year)},
+		{},
+		{"Fecha", "Entrada", "Salida", "Total horas", "Observaciones"},
+	}
+	for _, row := range r.Rows {
+		start, end := formatEntries(row)
+		records = append(records, []string{
+			row.Date.Format("02/01/2006"),
+			start,
+			end,
+			formatDuration(row.Total),
+			strings.Join(row.Observations, "; "),
+		})
+	}
+	records = append(records,
+		[]string{"Total", "", "", formatDuration(r.Total()), ""},
+		[]string{},
+		[]string{"Firma de la empresa", ""},
+		[]string{"Firma del trabajador", ""},
+	)
+
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
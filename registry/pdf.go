@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// WritePDF writes the registry as an A4 PDF document, with the company and
+// worker identifiers as header, a row per day and the signature boxes
+func (r Report) WritePDF(w io.Writer) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, tr("Registro de jornada"), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5, fmt.Sprintf("Periodo: %02d/%d", int(r.Month), r.Year), "", 1, "C", false, 0, "")
+	pdf.Ln(3)
+
+	header := [][2]string{
+		{"Empresa", r.Company.Name},
+		{"Trabajador", r.Worker.FullName},
+		{"CIF", r.Company.TaxID},
+		{"NIF", r.Worker.Identifier},
+		{"CCC", r.Company.ContributionID},
+		{"NAF", r.Worker.SocialSecurityNumber},
+		{"Centro de trabajo", r.Company.Workplace},
+		{"", ""},
+	}
+	for i, h := range header {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(32, 5, tr(h[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		ln := 0
+		if i%2 == 1 {
+			ln = 1
+		}
+		pdf.CellFormat(58, 5, tr(h[1]), "", ln, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	widths := []float64{22, 38, 38, 22, 60}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for i, title := range []string{"Fecha", "Entrada", "Salida", "Total horas", "Observaciones"} {
+		pdf.CellFormat(widths[i], 6, tr(title), "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 8)
+	for _, row := range r.Rows {
+		start, end := formatEntries(row)
+		cells := []string{
+			row.Date.Format("02/01/2006"),
+			start,
+			end,
+			formatDuration(row.Total),
+			strings.Join(row.Observations, "; "),
+		}
+		for i, cell := range cells {
+			align := "C"
+			if i == len(cells)-1 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 5, truncate(pdf, tr(cell), widths[i]), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2], 6, "Total", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 6, formatDuration(r.Total()), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(widths[4], 6, "", "1", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	// Signature placeholders, kept together on the same page
+	if _, pageHeight := pdf.GetPageSize(); pdf.GetY()+30 > pageHeight-15 {
+		pdf.AddPage()
+	}
+	y := pdf.GetY()
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.Rect(15, y, 85, 25, "D")
+	pdf.Rect(110, y, 85, 25, "D")
+	pdf.Text(17, y+4, tr("Firma de la empresa"))
+	pdf.Text(112, y+4, tr("Firma del trabajador"))
+
+	return pdf.Output(w)
+}
+
+// truncate shortens the text so it fits into the given cell width
+func truncate(pdf *gofpdf.Fpdf, text string, width float64) string {
+	const padding = 2
+	if pdf.GetStringWidth(text) <= width-padding {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width-padding {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
@@ -0,0 +1,213 @@
+// Package registry generates the monthly working-time registry (registro de jornada)
+// that Spanish companies must keep for each worker, with the daily clock-in and
+// clock-out records, exported as CSV or PDF for the labour inspection.
+package registry
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/timesheet"
+)
+
+// Client is the subset of the factorial.Client used for build the registry
+type Client interface {
+	ListShifts(filter url.Values) ([]factorial.Shift, error)
+	ListEmployees() ([]factorial.Employee, error)
+}
+
+// Company holds the identifiers of the company shown on the registry
+type Company struct {
+	Name           string
+	TaxID          string // CIF
+	ContributionID string // Código de cuenta de cotización (CCC)
+	Workplace      string // Centro de trabajo
+}
+
+// Worker holds the identifiers of the worker shown on the registry
+type Worker struct {
+	EmployeeID           int
+	FullName             string
+	Identifier           string // DNI, NIE or passport
+	SocialSecurityNumber string
+}
+
+// Entry is a single clock-in and clock-out record, End is zero
+// for the shifts that are still open
+type Entry struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Row is the registry of a worker for a single day
+type Row struct {
+	Date         time.Time
+	Entries      []Entry
+	Total        time.Duration
+	Observations []string
+}
+
+// Report is the monthly registry of a worker
+type Report struct {
+	Company Company
+	Worker  Worker
+	Year    int
+	Month   time.Month
+	Rows    []Row // One per day of the month
+}
+
+// Total returns the hours worked on the month
+func (r Report) Total() time.Duration {
+	var total time.Duration
+	for _, row := range r.Rows {
+		total += row.Total
+	}
+	return total
+}
+
+// Generate fetches the shifts of the given month and the employees and
+// returns the registry of every employee not terminated before the month
+func Generate(c Client, company Company, year int, month time.Month, loc *time.Location) ([]Report, error) {
+	filter := url.Values{}
+	filter.Set("year", strconv.Itoa(year))
+	filter.Set("month", strconv.Itoa(int(month)))
+	shifts, err := c.ListShifts(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	employees, err := c.ListEmployees()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReports(company, employees, shifts, year, month, loc)
+}
+
+// NewReports builds the registry of the given month for every employee
+// that was not terminated before the month or has shifts on it
+func NewReports(company Company, employees []factorial.Employee, shifts []factorial.Shift, year int, month time.Month, loc *time.Location) ([]Report, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+
+	byEmployee := make(map[int][]factorial.Shift)
+	for _, s := range shifts {
+		if s.Year == year && time.Month(s.Month) == month {
+			byEmployee[s.EmployeeID] = append(byEmployee[s.EmployeeID], s)
+		}
+	}
+
+	var reports []Report
+	for _, e := range employees {
+		if len(byEmployee[e.ID]) == 0 {
+			if terminated, err := timesheet.ParseDate(e.TerminatedOn, loc); err == nil && terminated.Before(first) {
+				continue
+			}
+		}
+		report, err := NewReport(company, e, byEmployee[e.ID], year, month, loc)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// NewReport builds the registry of the given month for the employee with the given shifts
+func NewReport(company Company, e factorial.Employee, shifts []factorial.Shift, year int, month time.Month, loc *time.Location) (Report, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	report := Report{
+		Company: company,
+		Worker: Worker{
+			EmployeeID:           e.ID,
+			FullName:             strings.TrimSpace(e.FullName),
+			Identifier:           e.Identifier,
+			SocialSecurityNumber: e.SocialSecurityNumber,
+		},
+		Year:  year,
+		Month: month,
+	}
+	if report.Worker.FullName == "" {
+		report.Worker.FullName = strings.TrimSpace(e.FirstName + " " + e.LastName)
+	}
+
+	rows := make(map[int]*Row)
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	for d := first; d.Month() == month; d = d.AddDate(0, 0, 1) {
+		report.Rows = append(report.Rows, Row{Date: d})
+	}
+	for i := range report.Rows {
+		rows[report.Rows[i].Date.Day()] = &report.Rows[i]
+	}
+
+	shifts = append([]factorial.Shift(nil), shifts...)
+	sort.Slice(shifts, func(i, j int) bool {
+		if shifts[i].Day != shifts[j].Day {
+			return shifts[i].Day < shifts[j].Day
+		}
+		return shifts[i].ClockIn < shifts[j].ClockIn
+	})
+	for _, s := range shifts {
+		row, ok := rows[s.Day]
+		if !ok || s.EmployeeID != e.ID {
+			continue
+		}
+		// Open shifts are registered without clock-out and don't count hours
+		interval, err := timesheet.ShiftInterval(s, loc, time.Time{})
+		if err != nil {
+			return report, err
+		}
+		entry := Entry{Start: interval.Start}
+		if !interval.Open {
+			entry.End = interval.End
+		}
+		row.Entries = append(row.Entries, entry)
+		row.Total += interval.Duration()
+		if obs := strings.TrimSpace(s.Observations); obs != "" {
+			row.Observations = append(row.Observations, obs)
+		}
+	}
+
+	return report, nil
+}
+
+// formatEntries returns the clock-in and clock-out times of the
+// row, joined when the day has more than one entry
+func formatEntries(row Row) (string, string) {
+	var starts, ends []string
+	for _, e := range row.Entries {
+		starts = append(starts, e.Start.Format("15:04"))
+		if e.End.IsZero() {
+			ends = append(ends, "-")
+			continue
+		}
+		end := e.End.Format("15:04")
+		if e.End.Day() != e.Start.Day() {
+			end += " (+1)"
+		}
+		ends = append(ends, end)
+	}
+	return strings.Join(starts, " / "), strings.Join(ends, " / ")
+}
+
+// formatDuration returns the duration as "HH:MM"
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	return twoDigits(minutes/60) + ":" + twoDigits(minutes%60)
+}
+
+func twoDigits(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
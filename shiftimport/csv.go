@@ -0,0 +1,135 @@
+package shiftimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// row is a parsed CSV row, err is set when it can't be imported
+type row struct {
+	line         int
+	employee     string
+	start        time.Time
+	end          time.Time
+	observations string
+	err          error
+}
+
+// readRows reads all the CSV rows using the header for find the mapped columns
+func readRows(r io.Reader, opts Options, loc *time.Location) ([]row, error) {
+	m := opts.Mapping
+	if m.Employee == "" || m.ClockIn == "" || m.ClockOut == "" {
+		return nil, errors.New("shiftimport: employee, clock in and clock out columns are required")
+	}
+
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("shiftimport: reading header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{m.Employee, m.Date, m.ClockIn, m.ClockOut, m.Observations} {
+		if _, ok := columns[name]; name != "" && !ok {
+			return nil, fmt.Errorf("shiftimport: column %q not found", name)
+		}
+	}
+
+	var rows []row
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("shiftimport: reading line %d: %w", line, err)
+		}
+
+		value := func(name string) string {
+			i, ok := columns[name]
+			if name == "" || !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		r := row{
+			line:         line,
+			employee:     value(m.Employee),
+			observations: value(m.Observations),
+		}
+		r.start, r.end, r.err = parseTimes(m, value(m.Date), value(m.ClockIn), value(m.ClockOut), loc)
+		if r.err == nil && r.employee == "" {
+			r.err = errors.New("missing employee")
+		}
+		rows = append(rows, r)
+	}
+
+	return rows, nil
+}
+
+// parseTimes returns the clock in and clock out of the row,
+// clock outs before the clock in are moved to the next day
+func parseTimes(m Mapping, date, clockIn, clockOut string, loc *time.Location) (time.Time, time.Time, error) {
+	if clockIn == "" || clockOut == "" {
+		return time.Time{}, time.Time{}, errors.New("missing clock in or clock out")
+	}
+
+	if m.Date == "" {
+		layout := m.TimeLayout
+		if layout == "" {
+			layout = "2006-01-02 15:04"
+		}
+		start, err := time.ParseInLocation(layout, clockIn, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid clock in %q", clockIn)
+		}
+		end, err := time.ParseInLocation(layout, clockOut, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid clock out %q", clockOut)
+		}
+		if !end.After(start) {
+			return time.Time{}, time.Time{}, errors.New("clock out is not after clock in")
+		}
+		return start, end, nil
+	}
+
+	dateLayout, timeLayout := m.DateLayout, m.TimeLayout
+	if dateLayout == "" {
+		dateLayout = "2006-01-02"
+	}
+	if timeLayout == "" {
+		timeLayout = "15:04"
+	}
+	day, err := time.ParseInLocation(dateLayout, date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", date)
+	}
+	in, err := time.Parse(timeLayout, clockIn)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid clock in %q", clockIn)
+	}
+	out, err := time.Parse(timeLayout, clockOut)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid clock out %q", clockOut)
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), in.Hour(), in.Minute(), in.Second(), 0, loc)
+	end := time.Date(day.Year(), day.Month(), day.Day(), out.Hour(), out.Minute(), out.Second(), 0, loc)
+	if !end.After(start) {
+		// Shift crossing midnight
+		end = time.Date(day.Year(), day.Month(), day.Day()+1, out.Hour(), out.Minute(), out.Second(), 0, loc)
+	}
+	return start, end, nil
+}
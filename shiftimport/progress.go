@@ -0,0 +1,63 @@
+package shiftimport
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/arexio/factorial-go/internal/progress"
+)
+
+// Progress keeps the rows already imported so an
+// interrupted import can be resumed
+type Progress interface {
+	Done(row int) bool
+	Mark(row int) error
+}
+
+// FileProgress is a Progress persisted on a file,
+// with the imported row numbers one per line
+type FileProgress struct {
+	mu   sync.Mutex
+	file *progress.File
+	done map[int]bool
+}
+
+// NewFileProgress loads the progress from the given file,
+// an empty progress is returned if the file doesn't exist
+func NewFileProgress(path string) (*FileProgress, error) {
+	p := &FileProgress{done: make(map[int]bool)}
+
+	file, err := progress.Open(path, func(line string) error {
+		row, err := strconv.Atoi(line)
+		if err != nil {
+			return fmt.Errorf("shiftimport: invalid progress line %q", line)
+		}
+		p.done[row] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.file = file
+
+	return p, nil
+}
+
+// Done returns whether the row was already imported
+func (p *FileProgress) Done(row int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done[row]
+}
+
+// Mark saves the row as imported
+func (p *FileProgress) Mark(row int) error {
+	if err := p.file.Append(row); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done[row] = true
+	return nil
+}
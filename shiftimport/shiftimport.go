@@ -0,0 +1,308 @@
+// Package shiftimport imports into Factorial the shifts exported
+// from hardware time clocks and other attendance systems as CSV.
+package shiftimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/timesheet"
+)
+
+// Ways of identifying the employee of each row
+const (
+	KeyEmail      = "email"
+	KeyIdentifier = "identifier" // National identification number
+	KeyCustom     = "custom"     // Custom ids resolved with Options.CustomIDs
+)
+
+// Status of an imported row
+const (
+	StatusCreated   = "created"
+	StatusDuplicate = "duplicate" // Already exists in Factorial
+	StatusResumed   = "resumed"   // Imported by a previous run
+	StatusFailed    = "failed"
+)
+
+// Client is the subset of the factorial.Client used by the importer
+type Client interface {
+	ListEmployees() ([]factorial.Employee, error)
+	ListShifts(filter url.Values) ([]factorial.Shift, error)
+	ClockIn(cin factorial.ClockInRequest) (factorial.Shift, error)
+	ClockOut(cout factorial.ClockOutRequest) (factorial.Shift, error)
+	UpdateShift(id string, d factorial.UpdateShiftRequest) (factorial.Shift, error)
+	DeleteShift(id string) error
+}
+
+// Mapping holds the name of the CSV columns for each field
+type Mapping struct {
+	Employee     string
+	Date         string // Optional when the clock columns contain the date
+	ClockIn      string
+	ClockOut     string
+	Observations string // Optional
+
+	DateLayout string // "2006-01-02" by default
+	TimeLayout string // "15:04" by default, or "2006-01-02 15:04" without Date column
+}
+
+// Options changes how the rows are read and imported
+type Options struct {
+	Mapping     Mapping
+	EmployeeKey string         // KeyEmail by default
+	CustomIDs   map[string]int // Custom id to EmployeeID, used with KeyCustom
+	Location    *time.Location // Timezone of the exported times, local time if nil
+	Comma       rune           // Field delimiter, ',' by default
+	Progress    Progress       // Rows already imported, for resume interrupted runs
+	DryRun      bool           // Validate the rows without creating the shifts
+}
+
+// RowResult is the result of importing a CSV row,
+// Row is the line number on the CSV including the header
+type RowResult struct {
+	Row        int
+	EmployeeID int
+	Start      time.Time
+	End        time.Time
+	Status     string
+	Error      string
+	ShiftID    int
+}
+
+// Report holds the result of every row
+type Report struct {
+	Rows []RowResult
+}
+
+// Count returns the number of rows with the given status
+func (r Report) Count(status string) int {
+	var n int
+	for _, row := range r.Rows {
+		if row.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteCSV writes the report as CSV, one line per row
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"row", "employee_id", "clock_in", "clock_out", "status", "shift_id", "error"}); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		record := []string{strconv.Itoa(row.Row), strconv.Itoa(row.EmployeeID), "", "", row.Status, "", row.Error}
+		if !row.Start.IsZero() {
+			record[2] = row.Start.Format(time.RFC3339)
+			record[3] = row.End.Format(time.RFC3339)
+		}
+		if row.ShiftID != 0 {
+			record[5] = strconv.Itoa(row.ShiftID)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Import reads the shifts from the CSV and creates them in Factorial with
+// ClockIn and ClockOut. Rows matching an existing shift are reported as
+// duplicates and rows overlapping other shifts of the employee as failed.
+func Import(c Client, r io.Reader, opts Options) (Report, error) {
+	var report Report
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	rows, err := readRows(r, opts, loc)
+	if err != nil {
+		return report, err
+	}
+
+	employees, err := c.ListEmployees()
+	if err != nil {
+		return report, err
+	}
+	resolve := employeeResolver(employees, opts)
+
+	existing, err := existingShifts(c, rows, loc)
+	if err != nil {
+		return report, err
+	}
+
+	for _, row := range rows {
+		result := RowResult{Row: row.line, Start: row.start, End: row.end}
+		if row.err != nil {
+			result.Status, result.Error = StatusFailed, row.err.Error()
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		employeeID, ok := resolve(row.employee)
+		if !ok {
+			result.Status, result.Error = StatusFailed, fmt.Sprintf("unknown employee %q", row.employee)
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+		result.EmployeeID = employeeID
+		interval := timesheet.Interval{EmployeeID: employeeID, Start: row.start, End: row.end}
+
+		if opts.Progress != nil && opts.Progress.Done(row.line) {
+			result.Status = StatusResumed
+			existing[employeeID] = append(existing[employeeID], interval)
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		if status, msg := check(existing[employeeID], interval); status != "" {
+			result.Status, result.Error = status, msg
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		if !opts.DryRun {
+			shift, err := create(c, employeeID, row)
+			if err != nil {
+				result.Status, result.Error = StatusFailed, err.Error()
+				report.Rows = append(report.Rows, result)
+				continue
+			}
+			result.ShiftID = shift.ID
+			if opts.Progress != nil {
+				if err := opts.Progress.Mark(row.line); err != nil {
+					return report, err
+				}
+			}
+		}
+		result.Status = StatusCreated
+		existing[employeeID] = append(existing[employeeID], interval)
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// check returns StatusDuplicate if the interval already exists,
+// or StatusFailed if it overlaps one of the given ones
+func check(intervals []timesheet.Interval, i timesheet.Interval) (string, string) {
+	for _, other := range intervals {
+		if other.Start.Equal(i.Start) && (other.Open || other.End.Equal(i.End)) {
+			return StatusDuplicate, ""
+		}
+	}
+	for _, other := range intervals {
+		if other.Open {
+			continue
+		}
+		if i.Start.Before(other.End) && other.Start.Before(i.End) {
+			return StatusFailed, fmt.Sprintf("overlaps shift from %s to %s",
+				other.Start.Format("2006-01-02 15:04"), other.End.Format("2006-01-02 15:04"))
+		}
+	}
+	return "", ""
+}
+
+// create opens and closes the shift, adding the observations if any.
+// If the shift can't be closed it is deleted, so it isn't left open
+// blocking the next clock ins of the employee.
+func create(c Client, employeeID int, r row) (factorial.Shift, error) {
+	opened, err := c.ClockIn(factorial.ClockInRequest{
+		Now:        r.start,
+		EmployeeID: employeeID,
+	})
+	if err != nil {
+		return opened, err
+	}
+	shift, err := c.ClockOut(factorial.ClockOutRequest{
+		Now:        r.end,
+		EmployeeID: employeeID,
+	})
+	if err != nil {
+		if delErr := c.DeleteShift(strconv.Itoa(opened.ID)); delErr != nil {
+			return factorial.Shift{}, fmt.Errorf("%v, and deleting the open shift %d failed: %v", err, opened.ID, delErr)
+		}
+		return factorial.Shift{}, err
+	}
+	if r.observations == "" {
+		return shift, nil
+	}
+
+	return c.UpdateShift(strconv.Itoa(shift.ID), factorial.UpdateShiftRequest{
+		ClockIn:      shift.ClockIn,
+		ClockOut:     shift.ClockOut,
+		Observations: r.observations,
+	})
+}
+
+// existingShifts fetches the shifts of every month covered by
+// the rows, grouped by employee
+func existingShifts(c Client, rows []row, loc *time.Location) (map[int][]timesheet.Interval, error) {
+	months := make(map[string]time.Time)
+	for _, r := range rows {
+		if r.err == nil {
+			months[r.start.Format("2006-01")] = r.start
+		}
+	}
+	keys := make([]string, 0, len(months))
+	for k := range months {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	existing := make(map[int][]timesheet.Interval)
+	for _, k := range keys {
+		filter := url.Values{}
+		filter.Set("year", strconv.Itoa(months[k].Year()))
+		filter.Set("month", strconv.Itoa(int(months[k].Month())))
+		shifts, err := c.ListShifts(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range shifts {
+			i, err := timesheet.ShiftInterval(s, loc, time.Time{})
+			if err != nil {
+				continue
+			}
+			existing[s.EmployeeID] = append(existing[s.EmployeeID], i)
+		}
+	}
+
+	return existing, nil
+}
+
+// employeeResolver returns a function that finds the EmployeeID
+// for the values of the employee column
+func employeeResolver(employees []factorial.Employee, opts Options) func(string) (int, bool) {
+	if opts.EmployeeKey == KeyCustom {
+		return func(key string) (int, bool) {
+			id, ok := opts.CustomIDs[strings.TrimSpace(key)]
+			return id, ok
+		}
+	}
+
+	ids := make(map[string]int)
+	for _, e := range employees {
+		key := e.Email
+		if opts.EmployeeKey == KeyIdentifier {
+			key = e.Identifier
+		}
+		if key != "" {
+			ids[strings.ToLower(strings.TrimSpace(key))] = e.ID
+		}
+	}
+	return func(key string) (int, bool) {
+		id, ok := ids[strings.ToLower(strings.TrimSpace(key))]
+		return id, ok
+	}
+}
@@ -0,0 +1,101 @@
+package shiftimport
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+// fakeClient keeps the open shift of each employee, rejecting clock ins
+// while one is open like Factorial does, and fails the clock outs of the
+// clock in times listed in failClockOut
+type fakeClient struct {
+	nextID       int
+	open         map[int]int
+	failClockOut map[string]bool
+	deleted      []string
+}
+
+func (f *fakeClient) ListEmployees() ([]factorial.Employee, error) {
+	return []factorial.Employee{{ID: 1, Email: "ann@example.com"}}, nil
+}
+
+func (f *fakeClient) ListShifts(filter url.Values) ([]factorial.Shift, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) ClockIn(cin factorial.ClockInRequest) (factorial.Shift, error) {
+	if _, ok := f.open[cin.EmployeeID]; ok {
+		return factorial.Shift{}, factorial.APIError{StatusCode: 422, Body: "already clocked in"}
+	}
+	f.nextID++
+	f.open[cin.EmployeeID] = f.nextID
+	return factorial.Shift{ID: f.nextID, EmployeeID: cin.EmployeeID, ClockIn: cin.Now.Format("15:04")}, nil
+}
+
+func (f *fakeClient) ClockOut(cout factorial.ClockOutRequest) (factorial.Shift, error) {
+	id := f.open[cout.EmployeeID]
+	if f.failClockOut[cout.Now.Format("15:04")] {
+		return factorial.Shift{}, errors.New("clock out failed")
+	}
+	delete(f.open, cout.EmployeeID)
+	return factorial.Shift{ID: id, EmployeeID: cout.EmployeeID, ClockOut: cout.Now.Format("15:04")}, nil
+}
+
+func (f *fakeClient) UpdateShift(id string, d factorial.UpdateShiftRequest) (factorial.Shift, error) {
+	n, _ := strconv.Atoi(id)
+	return factorial.Shift{ID: n}, nil
+}
+
+func (f *fakeClient) DeleteShift(id string) error {
+	f.deleted = append(f.deleted, id)
+	for employeeID, open := range f.open {
+		if strconv.Itoa(open) == id {
+			delete(f.open, employeeID)
+		}
+	}
+	return nil
+}
+
+type memoryProgress map[int]bool
+
+func (p memoryProgress) Done(row int) bool  { return p[row] }
+func (p memoryProgress) Mark(row int) error { p[row] = true; return nil }
+
+func TestImportDeletesShiftWhenClockOutFails(t *testing.T) {
+	c := &fakeClient{open: make(map[int]int), failClockOut: map[string]bool{"17:00": true}}
+	progress := memoryProgress{}
+	csv := "email,date,in,out\n" +
+		"ann@example.com,2024-03-04,09:00,17:00\n" +
+		"ann@example.com,2024-03-05,09:00,18:00\n"
+
+	report, err := Import(c, strings.NewReader(csv), Options{
+		Mapping:  Mapping{Employee: "email", Date: "date", ClockIn: "in", ClockOut: "out"},
+		Location: time.UTC,
+		Progress: progress,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := report.Rows[0]; got.Status != StatusFailed || got.ShiftID != 0 {
+		t.Errorf("first row = %+v, want failed without shift", got)
+	}
+	if len(c.deleted) != 1 || c.deleted[0] != "1" {
+		t.Errorf("deleted shifts = %v, want [1]", c.deleted)
+	}
+	if progress.Done(2) {
+		t.Error("failed row marked as imported")
+	}
+	if got := report.Rows[1]; got.Status != StatusCreated {
+		t.Errorf("second row = %+v, want created", got)
+	}
+	if len(c.open) != 0 {
+		t.Errorf("open shifts left = %v", c.open)
+	}
+}
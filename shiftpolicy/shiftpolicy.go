@@ -0,0 +1,232 @@
+// Package shiftpolicy finds the shifts that have been left open, because
+// someone forgot to clock out, and closes them at the contracted end time.
+package shiftpolicy
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/timesheet"
+)
+
+// Methods for close an open shift
+const (
+	// MethodUpdateShift sets the clock out and the note in a single UpdateShift call
+	MethodUpdateShift = "update_shift"
+	// MethodClockOut closes the shift with ClockOut and then adds the note with UpdateShift
+	MethodClockOut = "clock_out"
+)
+
+// Results of the policy over an open shift
+const (
+	ResultClosed     = "closed"
+	ResultWouldClose = "would_close" // The shift would be closed, on dry runs
+	ResultSkipped    = "skipped"
+	ResultFailed     = "failed"
+)
+
+// Client is the subset of the factorial.Client used by the policy
+type Client interface {
+	ListShifts(filter url.Values) ([]factorial.Shift, error)
+	ListHiringVersions(filter url.Values) ([]factorial.HiringVersion, error)
+	ClockOut(cout factorial.ClockOutRequest) (factorial.Shift, error)
+	UpdateShift(id string, d factorial.UpdateShiftRequest) (factorial.Shift, error)
+}
+
+// Policy defines when and how the open shifts are closed
+type Policy struct {
+	// Method used for close the shifts, MethodUpdateShift by default
+	Method string
+	// Grace is the time a shift can stay open after its contracted end,
+	// the one of DefaultPolicy if zero
+	Grace time.Duration
+	// DefaultDuration is used when the employee has no contracted hours
+	// that day, the one of DefaultPolicy if zero
+	DefaultDuration time.Duration
+	// Note added to the shift observations, "%s" is replaced by the clock out time
+	Note string
+	// Location of the shift clocks, local time if nil
+	Location *time.Location
+	// DryRun only reports the changes, nothing is applied
+	DryRun bool
+}
+
+// DefaultPolicy closes the shifts one hour after the contracted end,
+// using 8 hours when there is no contract, with UpdateShift
+func DefaultPolicy() Policy {
+	return Policy{
+		Method:          MethodUpdateShift,
+		Grace:           time.Hour,
+		DefaultDuration: 8 * time.Hour,
+		Note:            "Automatically clocked out at %s",
+	}
+}
+
+// Change is what the policy did with an open shift
+type Change struct {
+	Shift    factorial.Shift
+	ClockOut time.Time // Contracted end time used for close the shift
+	Result   string
+	Reason   string // Why the shift was skipped or failed, or why its note wasn't saved
+}
+
+// Report holds all the changes done by the policy
+type Report struct {
+	Changes []Change
+}
+
+// Count returns the number of changes with the given result
+func (r Report) Count(result string) int {
+	var n int
+	for _, ch := range r.Changes {
+		if ch.Result == result {
+			n++
+		}
+	}
+	return n
+}
+
+// Closed returns the number of shifts closed
+func (r Report) Closed() int {
+	return r.Count(ResultClosed)
+}
+
+// Print writes the report in a human readable way
+func (r Report) Print(w io.Writer) error {
+	for _, ch := range r.Changes {
+		line := fmt.Sprintf("%s shift %d of employee %d (%04d-%02d-%02d %s)",
+			ch.Result, ch.Shift.ID, ch.Shift.EmployeeID, ch.Shift.Year, ch.Shift.Month, ch.Shift.Day, ch.Shift.ClockIn)
+		if !ch.ClockOut.IsZero() {
+			line += " clock out " + ch.ClockOut.Format("2006-01-02 15:04")
+		}
+		if ch.Reason != "" {
+			line += ": " + ch.Reason
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	summary := fmt.Sprintf("%d open shift(s), %d closed", len(r.Changes), r.Closed())
+	if n := r.Count(ResultWouldClose); n > 0 {
+		summary += fmt.Sprintf(", %d would be closed", n)
+	}
+	_, err := fmt.Fprintln(w, summary+".")
+	return err
+}
+
+// Run finds the open shifts matching the filter and closes the ones
+// whose contracted end time plus the grace period is before now
+func Run(c Client, p Policy, filter url.Values, now time.Time) (Report, error) {
+	var report Report
+
+	loc := p.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	defaults := DefaultPolicy()
+	if p.Method == "" {
+		p.Method = defaults.Method
+	}
+	if p.Grace == 0 {
+		p.Grace = defaults.Grace
+	}
+	if p.DefaultDuration == 0 {
+		p.DefaultDuration = defaults.DefaultDuration
+	}
+
+	shifts, err := c.ListShifts(filter)
+	if err != nil {
+		return report, err
+	}
+	versions, err := c.ListHiringVersions(nil)
+	if err != nil {
+		return report, err
+	}
+
+	for _, s := range shifts {
+		if !s.IsOpen() {
+			continue
+		}
+		ch := Change{Shift: s}
+
+		interval, err := timesheet.ShiftInterval(s, loc, time.Time{})
+		if err != nil {
+			ch.Result, ch.Reason = ResultFailed, err.Error()
+			report.Changes = append(report.Changes, ch)
+			continue
+		}
+		ch.ClockOut = interval.Start.Add(p.contractedDuration(versions, s.EmployeeID, interval.Start))
+
+		switch {
+		case ch.ClockOut.Add(p.Grace).After(now):
+			ch.Result, ch.Reason = ResultSkipped, "contracted end time not reached"
+		case p.DryRun:
+			ch.Result = ResultWouldClose
+		default:
+			closed, err := p.close(c, s, ch.ClockOut)
+			switch {
+			case err == nil:
+				ch.Result = ResultClosed
+			case closed:
+				ch.Result, ch.Reason = ResultClosed, "note not saved: "+err.Error()
+			default:
+				ch.Result, ch.Reason = ResultFailed, err.Error()
+			}
+		}
+		report.Changes = append(report.Changes, ch)
+	}
+
+	return report, nil
+}
+
+// contractedDuration returns the hours the employee is expected to work on the given day
+func (p Policy) contractedDuration(versions []factorial.HiringVersion, employeeID int, day time.Time) time.Duration {
+	if hv, ok := timesheet.EffectiveHiringVersion(versions, employeeID, day); ok {
+		if d := timesheet.ContractedHours(hv, day); d > 0 {
+			return d
+		}
+	}
+	return p.DefaultDuration
+}
+
+// close sets the clock out of the shift with the configured method,
+// closed is true when the shift was clocked out even if saving the
+// note afterwards failed
+func (p Policy) close(c Client, s factorial.Shift, clockOut time.Time) (closed bool, err error) {
+	if p.Method == MethodClockOut {
+		if _, err := c.ClockOut(factorial.ClockOutRequest{
+			Now:        clockOut,
+			EmployeeID: s.EmployeeID,
+		}); err != nil {
+			return false, err
+		}
+		closed = true
+	}
+
+	_, err = c.UpdateShift(strconv.Itoa(s.ID), factorial.UpdateShiftRequest{
+		ClockIn:      s.ClockIn,
+		ClockOut:     clockOut.Format("15:04"),
+		Observations: p.observations(s, clockOut),
+	})
+	return closed || err == nil, err
+}
+
+// observations appends the policy note to the current shift observations
+func (p Policy) observations(s factorial.Shift, clockOut time.Time) string {
+	if p.Note == "" {
+		return s.Observations
+	}
+	note := p.Note
+	if strings.Contains(note, "%s") {
+		note = fmt.Sprintf(note, clockOut.Format("15:04"))
+	}
+	if s.Observations == "" {
+		return note
+	}
+	return s.Observations + "\n" + note
+}
@@ -2,7 +2,11 @@ package factorial
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/url"
+	"strings"
+	"time"
 )
 
 const (
@@ -11,6 +15,13 @@ const (
 	clockOutURL = shiftURL + "/clock_out"
 )
 
+// ClockTimeLayout is the layout expected by Factorial for
+// the time sent on ClockIn and ClockOut
+const ClockTimeLayout = "2006-01-02T15:04:05-0700"
+
+// ErrOpenShift is returned when asking for the clock out of an open shift
+var ErrOpenShift = errors.New("factorial: shift has not been clocked out")
+
 // Shift keeps the basic information related
 // with shifts in Factorial
 type Shift struct {
@@ -24,18 +35,139 @@ type Shift struct {
 	Observations string `json:"observations"`
 }
 
+// IsOpen returns whether the shift has not been clocked out yet
+func (s Shift) IsOpen() bool {
+	return s.ClockOut == ""
+}
+
+// ClockInTime returns the clock in of the shift on the given location, local time if nil.
+// Clocks are wall times of the shift day, times that don't exist because of a DST
+// change are moved forward, and repeated ones may resolve to either occurrence.
+func (s Shift) ClockInTime(loc *time.Location) (time.Time, error) {
+	return s.clockTime(s.ClockIn, loc)
+}
+
+// ClockOutTime returns the clock out of the shift on the given location, local time if nil.
+// Clock outs before the clock in are crossing midnight and finish the day after.
+// ErrOpenShift is returned for open shifts.
+func (s Shift) ClockOutTime(loc *time.Location) (time.Time, error) {
+	if s.IsOpen() {
+		return time.Time{}, ErrOpenShift
+	}
+	in, err := s.ClockInTime(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	out, err := s.clockTime(s.ClockOut, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if out.Before(in) {
+		out = time.Date(out.Year(), out.Month(), out.Day()+1, out.Hour(), out.Minute(), out.Second(), 0, out.Location())
+	}
+	return out, nil
+}
+
+// clockTime parses the given clock, "HH:MM" or "HH:MM:SS" on the shift day,
+// or a full timestamp that is converted to the given location
+func (s Shift) clockTime(clock string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	clock = strings.TrimSpace(clock)
+	for _, layout := range []string{time.RFC3339, ClockTimeLayout} {
+		if t, err := time.Parse(layout, clock); err == nil {
+			return t.In(loc), nil
+		}
+	}
+
+	layout := "15:04"
+	if strings.Count(clock, ":") == 2 {
+		layout = "15:04:05"
+	}
+	t, err := time.Parse(layout, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("factorial: invalid clock %q on shift %d", clock, s.ID)
+	}
+	return time.Date(s.Year, time.Month(s.Month), s.Day, t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+}
+
 // ClockInRequest will hold the basic information
-// needed for create a new shift (ClockIn) in Factorial
+// needed for create a new shift (ClockIn) in Factorial.
+// If Now is zero the current time is sent.
 type ClockInRequest struct {
-	Now        string `json:"now"`
-	EmployeeID int    `json:"employee_id"`
+	Now        time.Time
+	EmployeeID int
+}
+
+// MarshalJSON sends Now with the layout expected by Factorial
+func (r ClockInRequest) MarshalJSON() ([]byte, error) {
+	return marshalClock(r.Now, r.EmployeeID)
+}
+
+// UnmarshalJSON reads the body written by MarshalJSON
+func (r *ClockInRequest) UnmarshalJSON(b []byte) error {
+	var err error
+	r.Now, r.EmployeeID, err = unmarshalClock(b)
+	return err
 }
 
 // ClockOutRequest will hold the basic information
-// needed for create a new shift (ClockOut) in Factorial
+// needed for create a new shift (ClockOut) in Factorial.
+// If Now is zero the current time is sent.
 type ClockOutRequest struct {
-	Now        string `json:"now"`
-	EmployeeID int    `json:"employee_id"`
+	Now        time.Time
+	EmployeeID int
+}
+
+// MarshalJSON sends Now with the layout expected by Factorial
+func (r ClockOutRequest) MarshalJSON() ([]byte, error) {
+	return marshalClock(r.Now, r.EmployeeID)
+}
+
+// UnmarshalJSON reads the body written by MarshalJSON
+func (r *ClockOutRequest) UnmarshalJSON(b []byte) error {
+	var err error
+	r.Now, r.EmployeeID, err = unmarshalClock(b)
+	return err
+}
+
+// marshalClock encodes the body of the clock in and clock out requests,
+// the offset of now is kept so Factorial gets the employee's wall time
+func marshalClock(now time.Time, employeeID int) ([]byte, error) {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return json.Marshal(struct {
+		Now        string `json:"now"`
+		EmployeeID int    `json:"employee_id"`
+	}{
+		Now:        now.Format(ClockTimeLayout),
+		EmployeeID: employeeID,
+	})
+}
+
+// unmarshalClock decodes the body of the clock in and clock out requests,
+// now is read with ClockTimeLayout or RFC 3339
+func unmarshalClock(b []byte) (time.Time, int, error) {
+	var body struct {
+		Now        string `json:"now"`
+		EmployeeID int    `json:"employee_id"`
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return time.Time{}, 0, err
+	}
+	if body.Now == "" {
+		return time.Time{}, body.EmployeeID, nil
+	}
+
+	now, err := time.Parse(ClockTimeLayout, body.Now)
+	if err != nil {
+		if now, err = time.Parse(time.RFC3339, body.Now); err != nil {
+			return time.Time{}, 0, fmt.Errorf("factorial: invalid clock time %q", body.Now)
+		}
+	}
+	return now, body.EmployeeID, nil
 }
 
 // UpdateShiftRequest will hold the basic information
@@ -61,6 +193,9 @@ func (c Client) ClockIn(cin ClockInRequest) (Shift, error) {
 	if err != nil {
 		return shift, err
 	}
+	if err := checkResponse(resp); err != nil {
+		return shift, err
+	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&shift); err != nil {
 		return shift, err
@@ -83,6 +218,9 @@ func (c Client) ClockOut(cout ClockOutRequest) (Shift, error) {
 	if err != nil {
 		return shift, err
 	}
+	if err := checkResponse(resp); err != nil {
+		return shift, err
+	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&shift); err != nil {
 		return shift, err
@@ -93,10 +231,14 @@ func (c Client) ClockOut(cout ClockOutRequest) (Shift, error) {
 
 // DeleteShift will delete the given shiftID
 func (c Client) DeleteShift(id string) error {
-	_, err := c.delete(shiftURL + "/" + id)
+	resp, err := c.delete(shiftURL + "/" + id)
 	if err != nil {
 		return err
 	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	resp.Body.Close()
 
 	return nil
 }
@@ -134,6 +276,10 @@ func (c Client) UpdateShift(id string, d UpdateShiftRequest) (Shift, error) {
 	if err != nil {
 		return shift, err
 	}
+	if err := checkResponse(resp); err != nil {
+		return shift, err
+	}
+	defer resp.Body.Close()
 
 	if err := json.NewDecoder(resp.Body).Decode(&shift); err != nil {
 		return shift, err
@@ -141,3 +287,21 @@ func (c Client) UpdateShift(id string, d UpdateShiftRequest) (Shift, error) {
 
 	return shift, nil
 }
+
+// ListOpenShifts gets all the shifts that have not been clocked out yet,
+// the filter is the same as the one used by ListShifts
+func (c Client) ListOpenShifts(filter url.Values) ([]Shift, error) {
+	var open []Shift
+
+	shifts, err := c.ListShifts(filter)
+	if err != nil {
+		return open, err
+	}
+	for _, s := range shifts {
+		if s.IsOpen() {
+			open = append(open, s)
+		}
+	}
+
+	return open, nil
+}
@@ -0,0 +1,185 @@
+package factorial
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func madrid(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	return loc
+}
+
+func TestShiftClockTimesDST(t *testing.T) {
+	loc := madrid(t)
+
+	tests := []struct {
+		name     string
+		shift    Shift
+		in       []string // Accepted clock ins in UTC, repeated hours have two
+		out      string   // Clock out in UTC
+		duration time.Duration
+	}{
+		{
+			name:     "spring forward gap moves forward",
+			shift:    Shift{Year: 2024, Month: 3, Day: 31, ClockIn: "02:30", ClockOut: "10:00"},
+			in:       []string{"2024-03-31T01:30:00Z"},
+			out:      "2024-03-31T08:00:00Z",
+			duration: 6*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "autumn repeated hour",
+			shift:    Shift{Year: 2024, Month: 10, Day: 27, ClockIn: "02:30", ClockOut: "10:00"},
+			in:       []string{"2024-10-27T00:30:00Z", "2024-10-27T01:30:00Z"},
+			out:      "2024-10-27T09:00:00Z",
+			duration: -1, // Depends on the occurrence chosen
+		},
+		{
+			name:     "autumn repeated hour with offset",
+			shift:    Shift{Year: 2024, Month: 10, Day: 27, ClockIn: "2024-10-27T02:30:00+0100", ClockOut: "2024-10-27T10:00:00+0100"},
+			in:       []string{"2024-10-27T01:30:00Z"},
+			out:      "2024-10-27T09:00:00Z",
+			duration: 7*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "crossing midnight on spring forward night",
+			shift:    Shift{Year: 2024, Month: 3, Day: 30, ClockIn: "22:00", ClockOut: "06:00"},
+			in:       []string{"2024-03-30T21:00:00Z"},
+			out:      "2024-03-31T04:00:00Z",
+			duration: 7 * time.Hour,
+		},
+		{
+			name:     "crossing midnight on autumn night",
+			shift:    Shift{Year: 2024, Month: 10, Day: 26, ClockIn: "22:00", ClockOut: "06:00"},
+			in:       []string{"2024-10-26T20:00:00Z"},
+			out:      "2024-10-27T05:00:00Z",
+			duration: 9 * time.Hour,
+		},
+		{
+			name:     "crossing midnight at the end of the month",
+			shift:    Shift{Year: 2024, Month: 2, Day: 29, ClockIn: "23:00", ClockOut: "01:00"},
+			in:       []string{"2024-02-29T22:00:00Z"},
+			out:      "2024-03-01T00:00:00Z",
+			duration: 2 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := tt.shift.ClockInTime(loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := tt.shift.ClockOutTime(loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matched := false
+			for _, want := range tt.in {
+				matched = matched || in.UTC().Format(time.RFC3339) == want
+			}
+			if !matched {
+				t.Errorf("clock in = %s, want one of %v", in.UTC().Format(time.RFC3339), tt.in)
+			}
+			if got := out.UTC().Format(time.RFC3339); got != tt.out {
+				t.Errorf("clock out = %s, want %s", got, tt.out)
+			}
+			if tt.duration >= 0 && out.Sub(in) != tt.duration {
+				t.Errorf("duration = %s, want %s", out.Sub(in), tt.duration)
+			}
+		})
+	}
+}
+
+func TestShiftClockOutTimeOpen(t *testing.T) {
+	if _, err := (Shift{Year: 2024, Month: 3, Day: 31, ClockIn: "09:00"}).ClockOutTime(time.UTC); err != ErrOpenShift {
+		t.Errorf("err = %v, want ErrOpenShift", err)
+	}
+}
+
+func TestClockRequestMarshalJSON(t *testing.T) {
+	loc := madrid(t)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"winter time", time.Date(2024, 1, 15, 9, 0, 0, 0, loc), "2024-01-15T09:00:00+0100"},
+		{"summer time", time.Date(2024, 7, 15, 9, 0, 0, 0, loc), "2024-07-15T09:00:00+0200"},
+		{"after spring forward", time.Date(2024, 3, 31, 3, 0, 0, 0, loc), "2024-03-31T03:00:00+0200"},
+		{"first autumn 02:30", time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC).In(loc), "2024-10-27T02:30:00+0200"},
+		{"second autumn 02:30", time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC).In(loc), "2024-10-27T02:30:00+0100"},
+		{"utc", time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC), "2024-10-27T01:30:00+0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range []interface{}{
+				ClockInRequest{Now: tt.now, EmployeeID: 1},
+				ClockOutRequest{Now: tt.now, EmployeeID: 1},
+			} {
+				b, err := json.Marshal(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var body struct {
+					Now        string `json:"now"`
+					EmployeeID int    `json:"employee_id"`
+				}
+				if err := json.Unmarshal(b, &body); err != nil {
+					t.Fatal(err)
+				}
+				if body.Now != tt.want || body.EmployeeID != 1 {
+					t.Errorf("%T = %s, want now %s", v, b, tt.want)
+				}
+
+				parsed, err := time.Parse(ClockTimeLayout, body.Now)
+				if err != nil || !parsed.Equal(tt.now) {
+					t.Errorf("%s parsed back = %v, %v, want %v", body.Now, parsed, err, tt.now)
+				}
+
+				var in ClockInRequest
+				if err := json.Unmarshal(b, &in); err != nil || !in.Now.Equal(tt.now) || in.EmployeeID != 1 {
+					t.Errorf("%s unmarshalled = %+v, %v, want %v", b, in, err, tt.now)
+				}
+				var out ClockOutRequest
+				if err := json.Unmarshal(b, &out); err != nil || !out.Now.Equal(tt.now) || out.EmployeeID != 1 {
+					t.Errorf("%s unmarshalled = %+v, %v, want %v", b, out, err, tt.now)
+				}
+			}
+		})
+	}
+}
+
+func TestLocationTimeZone(t *testing.T) {
+	tests := []struct {
+		location Location
+		want     string
+	}{
+		{Location{Timezone: "Europe/Lisbon", Country: "es"}, "Europe/Lisbon"},
+		{Location{Country: "ES", State: "Madrid"}, "Europe/Madrid"},
+		{Location{Country: "es", State: "CN"}, "Atlantic/Canary"},
+		{Location{Country: "pt", State: "20"}, "Atlantic/Azores"},
+	}
+
+	for _, tt := range tests {
+		loc, err := tt.location.TimeZone()
+		if err != nil {
+			t.Skipf("time zone database not available: %v", err)
+		}
+		if loc.String() != tt.want {
+			t.Errorf("TimeZone(%+v) = %s, want %s", tt.location, loc, tt.want)
+		}
+	}
+
+	if _, err := (Location{Country: "xx"}).TimeZone(); err == nil {
+		t.Error("unknown country returned no error")
+	}
+}
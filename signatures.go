@@ -0,0 +1,152 @@
+package factorial
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	signatureURL = "/api/v1/signatures"
+)
+
+// Possible values for the status of a signature
+const (
+	SignatureStatusPending  = "pending"
+	SignatureStatusSigned   = "signed"
+	SignatureStatusDeclined = "declined"
+)
+
+// Events emitted by WaitForSignatures
+const (
+	SignatureEventSigned    = "signed"     // A signee signed the document
+	SignatureEventDeclined  = "declined"   // A signee declined to sign the document
+	SignatureEventAllSigned = "all_signed" // Every signee signed the document
+)
+
+// DefaultSignaturePollInterval is used by WaitForSignatures when no interval is given
+const DefaultSignaturePollInterval = 30 * time.Second
+
+// ErrSignatureDeclined is returned by WaitForSignatures
+// when a signee declines to sign the document
+var ErrSignatureDeclined = errors.New("factorial: e-signature declined")
+
+// Signature keeps the status of the e-signature
+// requested to a signee for a document
+type Signature struct {
+	ID         int    `json:"id"`
+	DocumentID int    `json:"document_id"`
+	EmployeeID int    `json:"employee_id"` // Signee
+	Status     string `json:"status"`      // Possible values: pending, signed, declined
+	SignedAt   string `json:"signed_at"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// SignatureEvent is emitted by WaitForSignatures when the
+// status of the signatures of a document changes
+type SignatureEvent struct {
+	Type       string
+	DocumentID int
+	Signature  Signature // Empty for SignatureEventAllSigned
+}
+
+// ListSignatures gets all the e-signatures requested in your company
+// you can filter this list by document_id, employee_id and status
+func (c Client) ListSignatures(filter url.Values) ([]Signature, error) {
+	var signatures []Signature
+
+	resp, err := c.get(signatureURL, filter)
+	if err != nil {
+		return signatures, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&signatures); err != nil {
+		return signatures, err
+	}
+
+	return signatures, nil
+}
+
+// ListPendingSignatures gets the e-signatures not signed yet,
+// the filter is the same as the one used by ListSignatures
+func (c Client) ListPendingSignatures(filter url.Values) ([]Signature, error) {
+	var pending []Signature
+
+	signatures, err := c.ListSignatures(filter)
+	if err != nil {
+		return pending, err
+	}
+	for _, s := range signatures {
+		if s.Status == SignatureStatusPending {
+			pending = append(pending, s)
+		}
+	}
+
+	return pending, nil
+}
+
+// WaitForSignatures polls the signatures of the given document every interval until
+// every signee has signed it, calling onEvent, if not nil, for each change. It stops
+// with ErrSignatureDeclined if a signee declines, or with the context error when the
+// context is done, use context.WithTimeout for limit the wait.
+// Intervals not greater than zero use DefaultSignaturePollInterval.
+func (c Client) WaitForSignatures(ctx context.Context, documentID int, interval time.Duration, onEvent func(SignatureEvent)) ([]Signature, error) {
+	filter := url.Values{}
+	filter.Set("document_id", strconv.Itoa(documentID))
+	emit := func(e SignatureEvent) {
+		if onEvent != nil {
+			onEvent(e)
+		}
+	}
+
+	if interval <= 0 {
+		interval = DefaultSignaturePollInterval
+	}
+	known := make(map[int]string)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		signatures, err := c.ListSignatures(filter)
+		if err != nil {
+			return signatures, err
+		}
+
+		var document []Signature
+		for _, s := range signatures {
+			if s.DocumentID == 0 || s.DocumentID == documentID {
+				document = append(document, s)
+			}
+		}
+
+		signed := 0
+		for _, s := range document {
+			previous, seen := known[s.ID]
+			known[s.ID] = s.Status
+			switch s.Status {
+			case SignatureStatusSigned:
+				signed++
+				if !seen || previous != s.Status {
+					emit(SignatureEvent{Type: SignatureEventSigned, DocumentID: documentID, Signature: s})
+				}
+			case SignatureStatusDeclined:
+				emit(SignatureEvent{Type: SignatureEventDeclined, DocumentID: documentID, Signature: s})
+				return document, ErrSignatureDeclined
+			}
+		}
+		if len(document) > 0 && signed == len(document) {
+			emit(SignatureEvent{Type: SignatureEventAllSigned, DocumentID: documentID})
+			return document, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return document, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
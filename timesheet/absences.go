@@ -0,0 +1,97 @@
+package timesheet
+
+import (
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+// absences keeps the days each employee is not expected to work,
+// by approved leaves or company holidays, with the ratio of the
+// contracted hours they cover: 1 for full days, 0.5 for half days
+type absences struct {
+	days map[int]map[string]float64
+}
+
+func newAbsences(in Input, loc *time.Location) absences {
+	a := absences{days: make(map[int]map[string]float64)}
+
+	workable := make(map[int]bool)
+	for _, lt := range in.LeaveTypes {
+		workable[lt.ID] = lt.Workable
+	}
+	for _, l := range in.Leaves {
+		if !countsAsAbsence(l) || workable[l.LeaveTypeID] {
+			continue
+		}
+		start, err := ParseDate(l.StartOn, loc)
+		if err != nil {
+			continue
+		}
+		finish, err := ParseDate(l.FinishOn, loc)
+		if err != nil {
+			finish = start
+		}
+		for d := start; !d.After(finish); d = d.AddDate(0, 0, 1) {
+			a.add(l.EmployeeID, d, l.HalfDay)
+		}
+	}
+
+	holidays := make(map[int]factorial.CompanyHoliday)
+	for _, h := range in.CompanyHolidays {
+		holidays[h.ID] = h
+	}
+	for _, e := range in.Employees {
+		applied := make(map[int]bool)
+		for _, id := range e.CompanyHolidayIDs {
+			applied[id] = true
+		}
+		for _, h := range in.CompanyHolidays {
+			if h.LocationID != 0 && h.LocationID == e.LocationID {
+				applied[h.ID] = true
+			}
+		}
+		for id := range applied {
+			h, ok := holidays[id]
+			if !ok {
+				continue
+			}
+			date, err := ParseDate(h.Date, loc)
+			if err != nil {
+				continue
+			}
+			a.add(e.ID, date, h.HalfDay)
+		}
+	}
+
+	return a
+}
+
+// add registers the absence of the employee on the given date, half
+// day absences add up so two of them on the same date cover the full day
+func (a absences) add(employeeID int, date time.Time, halfDay string) {
+	if a.days[employeeID] == nil {
+		a.days[employeeID] = make(map[string]float64)
+	}
+	day := date.Format(factorial.DateLayout)
+	ratio := 1.0
+	if halfDay != "" {
+		ratio = 0.5
+	}
+	a.days[employeeID][day] += ratio
+	if a.days[employeeID][day] > 1 {
+		a.days[employeeID][day] = 1
+	}
+}
+
+// ratio returns the part of the contracted hours not expected
+// to be worked by the employee on the given date
+func (a absences) ratio(employeeID int, date time.Time) float64 {
+	return a.days[employeeID][date.Format(factorial.DateLayout)]
+}
+
+// countsAsAbsence returns whether the leave reduces the contracted hours,
+// leaves without status come from accounts that don't require approval
+func countsAsAbsence(l factorial.Leave) bool {
+	return l.Status == factorial.LeaveStatusApproved || l.Status == ""
+}
@@ -0,0 +1,198 @@
+package timesheet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/dates"
+)
+
+// BreakRules defines how the breaks between shifts are paid
+// and the minimum break required for long working days
+type BreakRules struct {
+	// PaidAllowance is the break time per day that is paid,
+	// the break time over it is unpaid
+	PaidAllowance time.Duration
+	// MinBreakAfter is the working time after which a break is required
+	MinBreakAfter time.Duration
+	// MinBreak is the minimum duration of the required break
+	MinBreak time.Duration
+}
+
+// DefaultBreakRules requires a 15 minutes break after 6 hours of work,
+// as the Spanish Workers' Statute, and doesn't pay any break
+func DefaultBreakRules() BreakRules {
+	return BreakRules{
+		MinBreakAfter: 6 * time.Hour,
+		MinBreak:      15 * time.Minute,
+	}
+}
+
+// Break is the time between two consecutive segments of a workday
+type Break struct {
+	Start time.Time
+	End   time.Time
+	Paid  time.Duration // Part of the break covered by the paid allowance
+}
+
+// Duration returns the length of the break
+func (b Break) Duration() time.Duration {
+	return b.End.Sub(b.Start)
+}
+
+// Violation is reported when an employee worked longer than
+// MinBreakAfter without a break of at least MinBreak
+type Violation struct {
+	At     time.Time     // When the required break was due
+	Worked time.Duration // Time worked without a valid break
+}
+
+// Workday groups the shifts of an employee that start on the same day,
+// each shift is a segment and the gaps between them are breaks
+type Workday struct {
+	EmployeeID int
+	Date       time.Time
+	Segments   []Interval
+	Breaks     []Break
+	Violations []Violation
+}
+
+// Worked returns the time worked on the segments
+func (w Workday) Worked() time.Duration {
+	var worked time.Duration
+	for _, s := range w.Segments {
+		worked += s.Duration()
+	}
+	return worked
+}
+
+// BreakTime returns the total time of the breaks
+func (w Workday) BreakTime() time.Duration {
+	var total time.Duration
+	for _, b := range w.Breaks {
+		total += b.Duration()
+	}
+	return total
+}
+
+// PaidBreak returns the break time that is paid
+func (w Workday) PaidBreak() time.Duration {
+	var paid time.Duration
+	for _, b := range w.Breaks {
+		paid += b.Paid
+	}
+	return paid
+}
+
+// UnpaidBreak returns the break time that is not paid
+func (w Workday) UnpaidBreak() time.Duration {
+	return w.BreakTime() - w.PaidBreak()
+}
+
+// Paid returns the worked time plus the paid break time
+func (w Workday) Paid() time.Duration {
+	return w.Worked() + w.PaidBreak()
+}
+
+// Workdays groups the given intervals into workdays by employee and start day,
+// computing the breaks between them and the violations of the break rules.
+// Overlapping intervals are merged. The result is sorted by employee and date.
+func Workdays(intervals []Interval, rules BreakRules) []Workday {
+	type key struct {
+		employeeID int
+		date       string
+	}
+	groups := make(map[key]*Workday)
+	var keys []key
+	for _, i := range intervals {
+		date := dates.Truncate(i.Start)
+		k := key{i.EmployeeID, date.Format(factorial.DateLayout)}
+		w, ok := groups[k]
+		if !ok {
+			w = &Workday{EmployeeID: i.EmployeeID, Date: date}
+			groups[k] = w
+			keys = append(keys, k)
+		}
+		w.Segments = append(w.Segments, i)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].employeeID != keys[j].employeeID {
+			return keys[i].employeeID < keys[j].employeeID
+		}
+		return keys[i].date < keys[j].date
+	})
+	workdays := make([]Workday, 0, len(keys))
+	for _, k := range keys {
+		w := groups[k]
+		w.Segments = mergeSegments(w.Segments)
+		w.Breaks = breaks(w.Segments, rules.PaidAllowance)
+		w.Violations = violations(w.Segments, rules)
+		workdays = append(workdays, *w)
+	}
+	return workdays
+}
+
+// mergeSegments sorts the segments and merges the overlapping ones
+func mergeSegments(segments []Interval) []Interval {
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Start.Before(segments[j].Start)
+	})
+	merged := segments[:1]
+	for _, s := range segments[1:] {
+		last := &merged[len(merged)-1]
+		if s.Start.After(last.End) {
+			merged = append(merged, s)
+			continue
+		}
+		if s.End.After(last.End) {
+			last.End = s.End
+		}
+		last.Open = last.Open || s.Open
+	}
+	return merged
+}
+
+// breaks returns the gaps between the segments, the
+// paid allowance is spent on the first breaks of the day
+func breaks(segments []Interval, allowance time.Duration) []Break {
+	var result []Break
+	for i := 1; i < len(segments); i++ {
+		b := Break{Start: segments[i-1].End, End: segments[i].Start}
+		b.Paid = b.Duration()
+		if b.Paid > allowance {
+			b.Paid = allowance
+		}
+		allowance -= b.Paid
+		result = append(result, b)
+	}
+	return result
+}
+
+// violations walks the segments accumulating the worked time since the
+// last valid break, reporting when it goes over MinBreakAfter
+func violations(segments []Interval, rules BreakRules) []Violation {
+	if rules.MinBreakAfter <= 0 {
+		return nil
+	}
+
+	var result []Violation
+	var worked time.Duration
+	for i, s := range segments {
+		if i > 0 && s.Start.Sub(segments[i-1].End) >= rules.MinBreak {
+			worked = 0
+		}
+		before := worked
+		worked += s.Duration()
+		if before <= rules.MinBreakAfter && worked > rules.MinBreakAfter {
+			result = append(result, Violation{
+				At:     s.Start.Add(rules.MinBreakAfter - before),
+				Worked: worked,
+			})
+		} else if len(result) > 0 && before > rules.MinBreakAfter {
+			result[len(result)-1].Worked = worked
+		}
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package timesheet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/dates"
+)
+
+// workingDaysPerWeek is used for split the contracted hours between
+// the days of the week, contracted hours are only expected on weekdays
+const workingDaysPerWeek = 5
+
+// weeksPerYear is used for convert monthly and yearly working hours
+const weeksPerYear = 52
+
+// EffectiveHiringVersion returns the hiring version of the employee
+// effective on the given date, the one with the latest EffectiveOn
+// that is not after the date, or the one with the highest ID when several
+// are effective on the same day. The bool is false if there is none.
+func EffectiveHiringVersion(versions []factorial.HiringVersion, employeeID int, date time.Time) (factorial.HiringVersion, bool) {
+	var candidates []factorial.HiringVersion
+	for _, hv := range versions {
+		if hv.EmployeeID != employeeID {
+			continue
+		}
+		effective, err := ParseDate(hv.EffectiveOn, date.Location())
+		if err != nil || effective.After(date) {
+			continue
+		}
+		candidates = append(candidates, hv)
+	}
+	if len(candidates) == 0 {
+		return factorial.HiringVersion{}, false
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].EffectiveOn != candidates[j].EffectiveOn {
+			return candidates[i].EffectiveOn < candidates[j].EffectiveOn
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	return candidates[len(candidates)-1], true
+}
+
+// WeeklyHours returns the contracted hours per week of the hiring version,
+// days are worked from monday to friday and years have 52 weeks
+func WeeklyHours(hv factorial.HiringVersion) time.Duration {
+	// Working hours are in cents, 4000 means 40 hours
+	hours := time.Duration(hv.WorkingHoursInCents) * time.Hour / 100
+	switch hv.WorkingPeriodUnit {
+	case factorial.WorkingPeriodDay:
+		return hours * workingDaysPerWeek
+	case factorial.WorkingPeriodMonth:
+		return hours * 12 / weeksPerYear
+	case factorial.WorkingPeriodYear:
+		return hours / weeksPerYear
+	default:
+		return hours
+	}
+}
+
+// ContractedHours returns the hours the given hiring version expects
+// to be worked on the given date, the weekly hours split between the
+// weekdays. Weekends and days out of the contract StartDate and EndDate
+// expect no hours.
+func ContractedHours(hv factorial.HiringVersion, date time.Time) time.Duration {
+	day := dates.Truncate(date)
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return 0
+	}
+	if start, err := ParseDate(hv.StartDate, day.Location()); err == nil && day.Before(start) {
+		return 0
+	}
+	if end, err := ParseDate(hv.EndDate, day.Location()); err == nil && day.After(end) {
+		return 0
+	}
+
+	return WeeklyHours(hv) / workingDaysPerWeek
+}
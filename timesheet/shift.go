@@ -0,0 +1,85 @@
+package timesheet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arexio/factorial-go"
+)
+
+// Interval is the worked time of a shift, End is the clock out
+// time or, for open shifts, the moment used for compute them
+type Interval struct {
+	EmployeeID int
+	Start      time.Time
+	End        time.Time
+	Open       bool // Whether the shift has not been clocked out yet
+}
+
+// Duration returns the worked time of the interval
+func (i Interval) Duration() time.Duration {
+	if i.End.Before(i.Start) {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// ShiftInterval converts the given shift into an interval on the given location.
+// Shifts whose clock out is before their clock in are crossing midnight and finish
+// the day after. Open shifts finish at now, or at their start if now is zero or
+// previous to the clock in.
+func ShiftInterval(s factorial.Shift, loc *time.Location, now time.Time) (Interval, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	interval := Interval{EmployeeID: s.EmployeeID}
+
+	start, err := s.ClockInTime(loc)
+	if err != nil {
+		return interval, fmt.Errorf("timesheet: %w", err)
+	}
+	interval.Start = start
+
+	if s.IsOpen() {
+		interval.Open = true
+		interval.End = start
+		if now.After(start) {
+			interval.End = now.In(loc)
+		}
+		return interval, nil
+	}
+
+	end, err := s.ClockOutTime(loc)
+	if err != nil {
+		return interval, fmt.Errorf("timesheet: %w", err)
+	}
+	interval.End = end
+
+	return interval, nil
+}
+
+// ShiftIntervals converts all the given shifts, see ShiftInterval
+func ShiftIntervals(shifts []factorial.Shift, loc *time.Location, now time.Time) ([]Interval, error) {
+	intervals := make([]Interval, 0, len(shifts))
+	for _, s := range shifts {
+		i, err := ShiftInterval(s, loc, now)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, i)
+	}
+	return intervals, nil
+}
+
+// nextDay returns the midnight after the given one, on the same location
+func nextDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
+
+// ParseDate parses a Factorial date on the given location
+func ParseDate(date string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	return time.ParseInLocation(factorial.DateLayout, date, loc)
+}
@@ -0,0 +1,222 @@
+// Package timesheet aggregates the shifts of the employees into worked
+// hours per day, week and month, comparing them with the contracted hours
+// of their hiring versions to report overtime and undertime.
+package timesheet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/dates"
+)
+
+// Input holds all the data fetched from Factorial needed for
+// compute the timesheets
+type Input struct {
+	Shifts          []factorial.Shift
+	HiringVersions  []factorial.HiringVersion
+	Leaves          []factorial.Leave
+	LeaveTypes      []factorial.LeaveType
+	CompanyHolidays []factorial.CompanyHoliday
+	Employees       []factorial.Employee
+
+	// Location used for the shift clocks and the dates, local time if nil
+	Location *time.Location
+	// Now is used for compute the open shifts, open shifts count as
+	// zero hours if empty
+	Now time.Time
+}
+
+// Day is the timesheet of an employee for a given date
+type Day struct {
+	EmployeeID int
+	Date       time.Time
+	Worked     time.Duration
+	Expected   time.Duration // Contracted hours net of leaves and company holidays
+	Open       bool          // Whether an open shift has been counted on this day
+}
+
+// Balance returns the worked time minus the expected one
+func (d Day) Balance() time.Duration {
+	return d.Worked - d.Expected
+}
+
+// Overtime returns the time worked over the expected one
+func (d Day) Overtime() time.Duration {
+	return positive(d.Balance())
+}
+
+// Undertime returns the expected time that was not worked
+func (d Day) Undertime() time.Duration {
+	return positive(-d.Balance())
+}
+
+// Period is the timesheet of an employee for a range of days
+type Period struct {
+	EmployeeID int
+	Start      time.Time
+	End        time.Time
+	Worked     time.Duration
+	Expected   time.Duration
+	Overtime   time.Duration // Sum of the daily overtime
+	Undertime  time.Duration // Sum of the daily undertime
+	Open       bool
+	Days       []Day
+}
+
+// Balance returns the worked time minus the expected one
+func (p Period) Balance() time.Duration {
+	return p.Worked - p.Expected
+}
+
+// Compute returns the timesheet of every employee for each day
+// between from and to, both included, sorted by employee and date.
+// Employees are the ones given in the input plus the ones with shifts.
+func Compute(in Input, from, to time.Time) ([]Day, error) {
+	loc := in.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	from = dates.Truncate(from.In(loc))
+	to = dates.Truncate(to.In(loc))
+
+	intervals, err := ShiftIntervals(in.Shifts, loc, in.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		employeeID int
+		date       time.Time
+	}
+	worked := make(map[key]time.Duration)
+	open := make(map[key]bool)
+	for _, i := range intervals {
+		for _, part := range splitByDay(i) {
+			k := key{i.EmployeeID, dates.Truncate(part.Start)}
+			worked[k] += part.Duration()
+			open[k] = open[k] || i.Open
+		}
+	}
+
+	absences := newAbsences(in, loc)
+	var days []Day
+	for _, employeeID := range employeeIDs(in) {
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			k := key{employeeID, d}
+			day := Day{
+				EmployeeID: employeeID,
+				Date:       d,
+				Worked:     worked[k],
+				Open:       open[k],
+			}
+			if hv, ok := EffectiveHiringVersion(in.HiringVersions, employeeID, d); ok {
+				contracted := ContractedHours(hv, d)
+				day.Expected = contracted - time.Duration(float64(contracted)*absences.ratio(employeeID, d))
+			}
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
+
+// Weekly groups the given days into ISO weeks, starting on monday
+func Weekly(days []Day) []Period {
+	return group(days, func(d time.Time) (time.Time, time.Time) {
+		offset := (int(d.Weekday()) + 6) % 7
+		start := d.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 6)
+	})
+}
+
+// Monthly groups the given days into calendar months
+func Monthly(days []Day) []Period {
+	return group(days, func(d time.Time) (time.Time, time.Time) {
+		start := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, d.Location())
+		return start, start.AddDate(0, 1, -1)
+	})
+}
+
+// group sums the days of each employee into the periods
+// returned by bounds, sorted by employee and start
+func group(days []Day, bounds func(time.Time) (time.Time, time.Time)) []Period {
+	type key struct {
+		employeeID int
+		start      time.Time
+	}
+	periods := make(map[key]*Period)
+	var keys []key
+	for _, d := range days {
+		start, end := bounds(d.Date)
+		k := key{d.EmployeeID, start}
+		p, ok := periods[k]
+		if !ok {
+			p = &Period{EmployeeID: d.EmployeeID, Start: start, End: end}
+			periods[k] = p
+			keys = append(keys, k)
+		}
+		p.Worked += d.Worked
+		p.Expected += d.Expected
+		p.Overtime += d.Overtime()
+		p.Undertime += d.Undertime()
+		p.Open = p.Open || d.Open
+		p.Days = append(p.Days, d)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].employeeID != keys[j].employeeID {
+			return keys[i].employeeID < keys[j].employeeID
+		}
+		return keys[i].start.Before(keys[j].start)
+	})
+	result := make([]Period, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *periods[k])
+	}
+	return result
+}
+
+// splitByDay splits the interval at midnight so each
+// part can be counted on its own day
+func splitByDay(i Interval) []Interval {
+	var parts []Interval
+	start := i.Start
+	for {
+		midnight := nextDay(dates.Truncate(start))
+		if !i.End.After(midnight) {
+			parts = append(parts, Interval{EmployeeID: i.EmployeeID, Start: start, End: i.End, Open: i.Open})
+			return parts
+		}
+		parts = append(parts, Interval{EmployeeID: i.EmployeeID, Start: start, End: midnight, Open: i.Open})
+		start = midnight
+	}
+}
+
+// employeeIDs returns the sorted ids of the given employees and the ones with shifts
+func employeeIDs(in Input) []int {
+	seen := make(map[int]bool)
+	var ids []int
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, e := range in.Employees {
+		add(e.ID)
+	}
+	for _, s := range in.Shifts {
+		add(s.EmployeeID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
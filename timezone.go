@@ -0,0 +1,83 @@
+package factorial
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// countryTimezones maps the countries with a single time zone, or a main
+// one, to it. Used when the location doesn't provide its time zone.
+var countryTimezones = map[string]string{
+	"ad": "Europe/Andorra",
+	"at": "Europe/Vienna",
+	"be": "Europe/Brussels",
+	"ch": "Europe/Zurich",
+	"de": "Europe/Berlin",
+	"dk": "Europe/Copenhagen",
+	"es": "Europe/Madrid",
+	"fr": "Europe/Paris",
+	"gb": "Europe/London",
+	"ie": "Europe/Dublin",
+	"it": "Europe/Rome",
+	"lu": "Europe/Luxembourg",
+	"nl": "Europe/Amsterdam",
+	"pl": "Europe/Warsaw",
+	"pt": "Europe/Lisbon",
+	"se": "Europe/Stockholm",
+	"uk": "Europe/London",
+	"ar": "America/Argentina/Buenos_Aires",
+	"cl": "America/Santiago",
+	"co": "America/Bogota",
+	"pe": "America/Lima",
+}
+
+// stateTimezones maps the regions whose time zone differs
+// from the main one of their country
+var stateTimezones = map[string]string{
+	"es/cn": "Atlantic/Canary",
+	"es/gc": "Atlantic/Canary",
+	"es/tf": "Atlantic/Canary",
+	"pt/20": "Atlantic/Azores",
+	"pt/ac": "Atlantic/Azores",
+	"pt/30": "Atlantic/Madeira",
+	"pt/ma": "Atlantic/Madeira",
+}
+
+// TimeZone returns the time zone of the location, the Timezone field when
+// present or otherwise the one of its country and state
+func (l Location) TimeZone() (*time.Location, error) {
+	name := l.Timezone
+	if name == "" {
+		country := strings.ToLower(l.Country)
+		name = stateTimezones[country+"/"+strings.ToLower(l.State)]
+		if name == "" {
+			name = countryTimezones[country]
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("factorial: unknown time zone for location %d (%s)", l.ID, l.Country)
+	}
+
+	return time.LoadLocation(name)
+}
+
+// EmployeeTimeZone returns the time zone of the location of the given employee,
+// use it for build the Now of the clock requests and read back the shift clocks
+func (c Client) EmployeeTimeZone(employeeID int) (*time.Location, error) {
+	employee, err := c.GetEmployee(strconv.Itoa(employeeID))
+	if err != nil {
+		return nil, err
+	}
+	if employee.LocationID == 0 {
+		return nil, fmt.Errorf("factorial: employee %d has no location", employeeID)
+	}
+
+	location, err := c.GetLocation(strconv.Itoa(employee.LocationID))
+	if err != nil {
+		return nil, err
+	}
+
+	return location.TimeZone()
+}
@@ -13,6 +13,7 @@ const (
 // Webhook contains all the webhook information
 type Webhook struct {
 	SubscriptionType string `json:"subscription_type"`
+	TargetURL        string `json:"target_url"`
 }
 
 // CreateWebhookRequest keeps the information needed
@@ -0,0 +1,257 @@
+// Package workforce computes headcount and turnover analytics from the
+// employees. Every function works over already fetched data, so they can
+// be fed from ListEmployees, a cache or fixtures alike.
+package workforce
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/arexio/factorial-go"
+	"github.com/arexio/factorial-go/internal/dates"
+	"github.com/arexio/factorial-go/internal/teams"
+)
+
+// Dimensions of the breakdowns
+const (
+	DimensionTeam     = "team"
+	DimensionLocation = "location"
+	DimensionManager  = "manager"
+	DimensionGender   = "gender"
+)
+
+// Units of the periods of a series
+const (
+	UnitMonth   = "month"
+	UnitQuarter = "quarter"
+	UnitYear    = "year"
+)
+
+// Input is the data the breakdowns need for name the groups
+type Input struct {
+	Employees []factorial.Employee
+	Teams     []factorial.Team
+	Locations []factorial.Location
+}
+
+// Metrics of a period, from and to both inclusive
+type Metrics struct {
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	HeadcountStart int     `json:"headcount_start"`
+	HeadcountEnd   int     `json:"headcount_end"`
+	Hires          int     `json:"hires"`
+	Terminations   int     `json:"terminations"`
+	Turnover       float64 `json:"turnover_percent"`    // Terminations over the average headcount
+	Retention      float64 `json:"retention_percent"`   // Employees of the start still working at the end
+	AverageTenure  float64 `json:"average_tenure_days"` // Of the employees working at the end
+}
+
+// GroupMetrics are the metrics of the employees of a group
+type GroupMetrics struct {
+	Dimension string `json:"dimension"`
+	Group     string `json:"group"`
+	Metrics
+}
+
+// Active returns whether the employee works on the given date, the
+// termination date is the last day worked. Employees without start
+// date are considered working since always.
+func Active(e factorial.Employee, date time.Time) bool {
+	day := date.Format(factorial.DateLayout)
+	if e.StartDate != "" && e.StartDate > day {
+		return false
+	}
+	return e.TerminatedOn == "" || e.TerminatedOn >= day
+}
+
+// Headcount returns the number of employees working on the given date
+func Headcount(employees []factorial.Employee, date time.Time) int {
+	var n int
+	for _, e := range employees {
+		if Active(e, date) {
+			n++
+		}
+	}
+	return n
+}
+
+// Hires returns the employees that started between from and to, both inclusive
+func Hires(employees []factorial.Employee, from, to time.Time) []factorial.Employee {
+	return between(employees, from, to, func(e factorial.Employee) string { return e.StartDate })
+}
+
+// Terminations returns the employees terminated between from and to, both inclusive
+func Terminations(employees []factorial.Employee, from, to time.Time) []factorial.Employee {
+	return between(employees, from, to, func(e factorial.Employee) string { return e.TerminatedOn })
+}
+
+// Tenure returns the time the employee has been working on the given date,
+// until the termination date if it was before. Zero if they haven't started.
+func Tenure(e factorial.Employee, date time.Time) time.Duration {
+	start, err := time.ParseInLocation(factorial.DateLayout, e.StartDate, date.Location())
+	if err != nil {
+		return 0
+	}
+	end := dates.Truncate(date)
+	if terminated, err := time.ParseInLocation(factorial.DateLayout, e.TerminatedOn, date.Location()); err == nil && terminated.Before(end) {
+		end = terminated
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// AverageTenure returns the average tenure in days of the employees
+// working on the given date
+func AverageTenure(employees []factorial.Employee, date time.Time) float64 {
+	var total time.Duration
+	var n int
+	for _, e := range employees {
+		if !Active(e, date) {
+			continue
+		}
+		total += Tenure(e, date)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return total.Hours() / 24 / float64(n)
+}
+
+// Compute returns the metrics of the employees between from and to, both inclusive
+func Compute(employees []factorial.Employee, from, to time.Time) Metrics {
+	m := Metrics{
+		From:           from.Format(factorial.DateLayout),
+		To:             to.Format(factorial.DateLayout),
+		HeadcountStart: Headcount(employees, from),
+		HeadcountEnd:   Headcount(employees, to),
+		Hires:          len(Hires(employees, from, to)),
+		Terminations:   len(Terminations(employees, from, to)),
+		AverageTenure:  AverageTenure(employees, to),
+	}
+
+	if average := float64(m.HeadcountStart+m.HeadcountEnd) / 2; average > 0 {
+		m.Turnover = float64(m.Terminations) * 100 / average
+	}
+	if m.HeadcountStart > 0 {
+		var retained int
+		for _, e := range employees {
+			if Active(e, from) && Active(e, to) {
+				retained++
+			}
+		}
+		m.Retention = float64(retained) * 100 / float64(m.HeadcountStart)
+	}
+	return m
+}
+
+// Series returns the metrics of each month, quarter or year between from
+// and to. Periods start on the first day of the unit, the first and the last
+// ones are cut to from and to.
+func Series(employees []factorial.Employee, from, to time.Time, unit string) []Metrics {
+	var series []Metrics
+	start := dates.Truncate(from)
+	end := dates.Truncate(to)
+	for !start.After(end) {
+		next := nextPeriod(start, unit)
+		last := next.AddDate(0, 0, -1)
+		if last.After(end) {
+			last = end
+		}
+		series = append(series, Compute(employees, start, last))
+		start = next
+	}
+	return series
+}
+
+// Breakdown returns the metrics between from and to of each group of the
+// dimension, sorted by group. Employees in several teams count on each.
+func Breakdown(in Input, dimension string, from, to time.Time) []GroupMetrics {
+	groupsOf := grouper(in, dimension)
+	groups := make(map[string][]factorial.Employee)
+	for _, e := range in.Employees {
+		for _, g := range groupsOf(e) {
+			groups[g] = append(groups[g], e)
+		}
+	}
+
+	result := make([]GroupMetrics, 0, len(groups))
+	for g, employees := range groups {
+		result = append(result, GroupMetrics{Dimension: dimension, Group: g, Metrics: Compute(employees, from, to)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Group < result[j].Group })
+	return result
+}
+
+// grouper returns a function that gives the names of the groups
+// of an employee on the dimension
+func grouper(in Input, dimension string) func(e factorial.Employee) []string {
+	byEmployee := make(map[int][]string)
+	switch dimension {
+	case DimensionTeam:
+		byEmployee = teams.ByEmployee(in.Teams, in.Employees)
+	case DimensionLocation:
+		locations := make(map[int]string)
+		for _, l := range in.Locations {
+			locations[l.ID] = l.Name
+		}
+		for _, e := range in.Employees {
+			if name, ok := locations[e.LocationID]; ok {
+				byEmployee[e.ID] = []string{name}
+			}
+		}
+	case DimensionManager:
+		names := make(map[int]string)
+		for _, m := range in.Employees {
+			names[m.ID] = m.FullName
+			if m.FullName == "" {
+				names[m.ID] = "Employee " + strconv.Itoa(m.ID)
+			}
+		}
+		for _, e := range in.Employees {
+			if name, ok := names[e.ManagerID]; ok && e.ManagerID != 0 {
+				byEmployee[e.ID] = []string{name}
+			}
+		}
+	case DimensionGender:
+		for _, e := range in.Employees {
+			if e.Gender != "" {
+				byEmployee[e.ID] = []string{e.Gender}
+			}
+		}
+	}
+
+	return func(e factorial.Employee) []string {
+		if len(byEmployee[e.ID]) == 0 {
+			return []string{"Unknown"}
+		}
+		return byEmployee[e.ID]
+	}
+}
+
+func between(employees []factorial.Employee, from, to time.Time, date func(factorial.Employee) string) []factorial.Employee {
+	first, last := from.Format(factorial.DateLayout), to.Format(factorial.DateLayout)
+	var result []factorial.Employee
+	for _, e := range employees {
+		if d := date(e); d != "" && d >= first && d <= last {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func nextPeriod(date time.Time, unit string) time.Time {
+	switch unit {
+	case UnitYear:
+		return time.Date(date.Year()+1, 1, 1, 0, 0, 0, 0, date.Location())
+	case UnitQuarter:
+		month := (int(date.Month())-1)/3*3 + 1
+		return time.Date(date.Year(), time.Month(month)+3, 1, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, date.Location())
+	}
+}